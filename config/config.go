@@ -12,6 +12,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,6 +21,7 @@ var DebugFlags = [...]string{
 	"cpu",
 	"panic",
 	"parse",
+	"time",
 	"tokens",
 	"types",
 }
@@ -28,13 +30,22 @@ var DebugFlags = [...]string{
 // The zero value of a Config represents the default values for all settings.
 type Config struct {
 	prompt      string
+	prompt2     string // Continuation prompt.
+	input       io.Reader
 	output      io.Writer
 	errOutput   io.Writer
-	format      string
-	ratFormat   string
-	formatVerb  byte // The verb if format is floating-point.
-	formatPrec  int  // The precision if format is floating-point.
-	formatFloat bool // Whether format is floating-point.
+	format      formatSpec
+	typeFormat  map[string]formatSpec // Formats for individual types, by name.
+	ratDecimal  bool                  // Print rationals as floating-point.
+	color       bool                  // Color output on terminals.
+	nan         bool                  // Produce Inf and NaN rather than errors.
+	tolerance   float64               // Relative tolerance for comparisons; 0 means exact.
+	serial      bool                  // Never reduce in parallel.
+	overflow    bool                  // Warn when Int results are promoted to BigInt.
+	shell       bool                  // Allow sh to run shell commands.
+	notation    bool                  // Whether fixedLow and fixedHigh apply.
+	fixedLow    int                   // Lowest exponent printed in fixed notation.
+	fixedHigh   int                   // Lowest exponent too high for fixed notation.
 	origin      int
 	bigOrigin   *big.Int
 	seed        int64
@@ -43,8 +54,20 @@ type Config struct {
 	random      *rand.Rand
 	maxBits     uint          // Maximum length of an integer; 0 means no limit.
 	maxDigits   uint          // Above this size, ints print in floating format.
+	maxDepth    uint          // Maximum depth of calls of user-defined ops; 0 means no limit.
+	intBits     uint          // Width of wrapping integer arithmetic; 0 means arbitrary precision.
 	floatPrec   uint          // Length of mantissa of a BigFloat.
 	cpuTime     time.Duration // Elapsed time of last interactive command.
+	interrupt   int32         // Set atomically to 1 to stop the current evaluation.
+	evaluating  int32         // Set atomically to 1 while an evaluation runs.
+	timing      int32         // Set atomically; identifies the evaluation being timed, if any.
+	timedOut    int32         // Set atomically to the value of timing when that evaluation has run too long.
+	timeout     time.Duration // Longest time an evaluation may take; 0 means no limit.
+	historySize uint          // Number of input lines remembered for )history.
+	outputWidth int           // Width at which to wrap output; 0 means no limit.
+	groupSep    string        // Separator between digit groups in decimal integers; empty means none.
+	groupSize   int           // Number of digits in each group.
+	dateFormat  string        // Layout for printing times; empty means RFC 3339.
 	// Bases: 0 means C-like, base 10 with 07 for octal and 0xa for hex.
 	inputBase  int
 	outputBase int
@@ -52,6 +75,7 @@ type Config struct {
 
 func (c *Config) init() {
 	if c.output == nil {
+		c.input = os.Stdin
 		c.output = os.Stdout
 		c.errOutput = os.Stderr
 		c.origin = 1
@@ -60,10 +84,25 @@ func (c *Config) init() {
 		c.random = rand.New(c.source)
 		c.maxBits = 1e6
 		c.maxDigits = 1e4
+		c.maxDepth = 1e4
 		c.floatPrec = 256
+		c.historySize = 100
+		c.prompt2 = DefaultPrompt2
 	}
 }
 
+// Input returns the reader from which readv reads data.
+func (c *Config) Input() io.Reader {
+	c.init()
+	return c.input
+}
+
+// SetInput sets the reader from which readv reads data; default is os.Stdin.
+func (c *Config) SetInput(input io.Reader) {
+	c.init()
+	c.input = input
+}
+
 // Output returns the writer to be used for program output.
 func (c *Config) Output() io.Writer {
 	c.init()
@@ -88,53 +127,233 @@ func (c *Config) SetErrOutput(output io.Writer) {
 	c.errOutput = output
 }
 
-// Format returns the formatting string. If empty, the default
-// formatting is used, as defined by the bases.
-func (c *Config) Format() string {
-	return c.format
+// Color reports whether output to a terminal is to be colored.
+func (c *Config) Color() bool {
+	return c.color
 }
 
-// Format returns the formatting string for rationals.
-func (c *Config) RatFormat() string {
-	return c.ratFormat
+// SetColor sets whether output to a terminal is to be colored.
+// Output that is not to a terminal is never colored.
+func (c *Config) SetColor(color bool) {
+	c.init()
+	c.color = color
 }
 
-// SetFormat sets the formatting string. Rational formatting
-// is just this format applied twice with a / in between.
-func (c *Config) SetFormat(s string) {
+// NaN reports whether arithmetic produces the special values Inf and
+// NaN, as in IEEE floating point, rather than reporting an error.
+func (c *Config) NaN() bool {
+	return c.nan
+}
+
+// SetNaN sets whether arithmetic produces the special values Inf and
+// NaN rather than reporting an error.
+func (c *Config) SetNaN(nan bool) {
+	c.init()
+	c.nan = nan
+}
+
+// WarnOverflow reports whether to print a warning when the result of
+// an integer operation overflows an Int and is promoted to BigInt.
+func (c *Config) WarnOverflow() bool {
+	return c.overflow
+}
+
+// SetWarnOverflow sets whether to print a warning when the result of
+// an integer operation is promoted to BigInt.
+func (c *Config) SetWarnOverflow(warn bool) {
+	c.init()
+	c.overflow = warn
+}
+
+// Shell reports whether the sh operator may run shell commands.
+func (c *Config) Shell() bool {
+	return c.shell
+}
+
+// SetShell sets whether the sh operator may run shell commands.
+func (c *Config) SetShell(shell bool) {
+	c.init()
+	c.shell = shell
+}
+
+// Parallel reports whether reductions of large vectors may be
+// computed in parallel.
+func (c *Config) Parallel() bool {
+	return !c.serial
+}
+
+// SetParallel sets whether reductions of large vectors may be computed
+// in parallel.
+func (c *Config) SetParallel(parallel bool) {
+	c.init()
+	c.serial = !parallel
+}
+
+// Tolerance returns the relative tolerance within which rationals
+// and floats compare equal. Zero means comparisons are exact.
+func (c *Config) Tolerance() float64 {
+	return c.tolerance
+}
+
+// SetTolerance sets the relative tolerance for comparisons.
+func (c *Config) SetTolerance(tolerance float64) {
+	c.init()
+	c.tolerance = tolerance
+}
+
+// RationalDecimal reports whether rationals are printed as their
+// floating-point value rather than exactly.
+func (c *Config) RationalDecimal() bool {
+	return c.ratDecimal
+}
+
+// SetRationalDecimal sets whether rationals are printed as their
+// floating-point value, rounded to the floating-point precision.
+// Their values, and computation with them, remain exact.
+func (c *Config) SetRationalDecimal(decimal bool) {
+	c.init()
+	c.ratDecimal = decimal
+}
+
+// Notation returns the range of decimal exponents, from low up to but
+// not including high, for which floating-point values printed with a
+// %g format use fixed rather than scientific notation. If ok is false,
+// there is no such range and the usual rules of %g apply.
+func (c *Config) Notation() (low, high int, ok bool) {
+	return c.fixedLow, c.fixedHigh, c.notation
+}
+
+// SetNotation sets the range of decimal exponents for which
+// floating-point values are printed in fixed notation. An empty
+// range means that scientific notation is always used.
+func (c *Config) SetNotation(low, high int) {
 	c.init()
-	c.formatVerb = 0
-	c.formatPrec = 0
-	c.formatFloat = false
-	c.format = s
+	c.notation = true
+	c.fixedLow, c.fixedHigh = low, high
+}
+
+// ResetNotation restores the usual rules of %g for choosing between
+// fixed and scientific notation.
+func (c *Config) ResetNotation() {
+	c.init()
+	c.notation = false
+	c.fixedLow, c.fixedHigh = 0, 0
+}
+
+// FormatTypes lists the names of the types that may have their own
+// formats, as set by SetTypeFormat.
+var FormatTypes = [...]string{
+	"int",
+	"rat",
+	"float",
+}
+
+// A formatSpec holds a formatting string and what we know about it.
+type formatSpec struct {
+	format      string
+	ratFormat   string
+	formatVerb  byte // The verb if format is floating-point.
+	formatPrec  int  // The precision if format is floating-point.
+	formatFloat bool // Whether format is floating-point.
+}
+
+// newFormatSpec parses the formatting string. Rational formatting
+// is just this format applied twice with a / in between.
+func newFormatSpec(s string) formatSpec {
+	f := formatSpec{format: s}
 	if s == "" {
-		c.ratFormat = "%v/%v"
-		return
+		f.ratFormat = "%v/%v"
+		return f
 	}
-	c.ratFormat = s + "/" + s
+	f.ratFormat = s + "/" + s
 	// Is it a floating-point format?
 	switch s[len(s)-1] {
 	case 'f', 'F', 'g', 'G', 'e', 'E':
 		// Yes
 	default:
-		return
+		return f
 	}
-	c.formatFloat = true
-	c.formatVerb = s[len(s)-1]
-	c.formatPrec = 6 // The default
+	f.formatFloat = true
+	f.formatVerb = s[len(s)-1]
+	f.formatPrec = 6 // The default
 	point := strings.LastIndex(s, ".")
 	if point > 0 {
 		prec, err := strconv.ParseInt(s[point+1:len(s)-1], 10, 32)
 		if err == nil && prec >= 0 {
-			c.formatPrec = int(prec)
+			f.formatPrec = int(prec)
 		}
 	}
+	return f
+}
+
+// Format returns the formatting string. If empty, the default
+// formatting is used, as defined by the bases.
+func (c *Config) Format() string {
+	return c.format.format
+}
+
+// Format returns the formatting string for rationals.
+func (c *Config) RatFormat() string {
+	return c.format.ratFormat
+}
+
+// SetFormat sets the formatting string. Rational formatting
+// is just this format applied twice with a / in between.
+func (c *Config) SetFormat(s string) {
+	c.init()
+	c.format = newFormatSpec(s)
 }
 
 // FloatFormat returns the parsed information about the format,
 // if it's a floating-point format.
 func (c *Config) FloatFormat() (verb byte, prec int, ok bool) {
-	return c.formatVerb, c.formatPrec, c.formatFloat
+	return c.format.formatVerb, c.format.formatPrec, c.format.formatFloat
+}
+
+// TypeFormat returns the formatting string set for the named type,
+// one of FormatTypes, or the empty string if there is none.
+func (c *Config) TypeFormat(typ string) string {
+	return c.typeFormat[typ].format
+}
+
+// SetTypeFormat sets the formatting string for the named type, which
+// must be one of FormatTypes. Values of that type are printed using it
+// in preference to the general format. An empty string removes it.
+func (c *Config) SetTypeFormat(typ, s string) {
+	c.init()
+	if s == "" {
+		delete(c.typeFormat, typ)
+		return
+	}
+	if c.typeFormat == nil {
+		c.typeFormat = make(map[string]formatSpec)
+	}
+	c.typeFormat[typ] = newFormatSpec(s)
+}
+
+// formatFor returns the format to use for the named type.
+func (c *Config) formatFor(typ string) *formatSpec {
+	if f, ok := c.typeFormat[typ]; ok {
+		return &f
+	}
+	return &c.format
+}
+
+// FormatFor is like Format, but returns the format for the named type,
+// one of FormatTypes: its own format if it has one, or the general one.
+func (c *Config) FormatFor(typ string) string {
+	return c.formatFor(typ).format
+}
+
+// RatFormatFor is like RatFormat for the format of the named type.
+func (c *Config) RatFormatFor(typ string) string {
+	return c.formatFor(typ).ratFormat
+}
+
+// FloatFormatFor is like FloatFormat for the format of the named type.
+func (c *Config) FloatFormatFor(typ string) (verb byte, prec int, ok bool) {
+	f := c.formatFor(typ)
+	return f.formatVerb, f.formatPrec, f.formatFloat
 }
 
 // Debug returns the value of the specified boolean debugging flag.
@@ -188,6 +407,21 @@ func (c *Config) SetPrompt(prompt string) {
 	c.prompt = prompt
 }
 
+// DefaultPrompt2 is the default continuation prompt.
+const DefaultPrompt2 = "    "
+
+// Prompt2 returns the prompt for the continuation of an incomplete line.
+func (c *Config) Prompt2() string {
+	c.init()
+	return c.prompt2
+}
+
+// SetPrompt2 sets the prompt for the continuation of an incomplete line.
+func (c *Config) SetPrompt2(prompt string) {
+	c.init()
+	c.prompt2 = prompt
+}
+
 // Random returns the generator for random numbers.
 func (c *Config) Random() *rand.Rand {
 	c.init()
@@ -230,6 +464,33 @@ func (c *Config) SetMaxDigits(digits uint) {
 	c.maxDigits = digits
 }
 
+// MaxDepth returns the maximum depth of nested calls of user-defined
+// operators.
+func (c *Config) MaxDepth() uint {
+	c.init()
+	return c.maxDepth
+}
+
+// SetMaxDepth sets the maximum depth of nested calls of user-defined
+// operators.
+func (c *Config) SetMaxDepth(depth uint) {
+	c.init()
+	c.maxDepth = depth
+}
+
+// IntBits returns the width in bits of integer arithmetic, which wraps
+// around in two's complement, or 0 if integers have arbitrary precision.
+func (c *Config) IntBits() uint {
+	return c.intBits
+}
+
+// SetIntBits sets the width in bits of integer arithmetic. If it is 0,
+// integers have arbitrary precision.
+func (c *Config) SetIntBits(bits uint) {
+	c.init()
+	c.intBits = bits
+}
+
 // FloatPrec returns the floating-point precision in bits.
 // The exponent size is fixed by math/big.
 func (c *Config) FloatPrec() uint {
@@ -246,6 +507,59 @@ func (c *Config) SetFloatPrec(prec uint) {
 	c.floatPrec = prec
 }
 
+// HistorySize returns the number of input lines remembered for )history.
+func (c *Config) HistorySize() uint {
+	c.init()
+	return c.historySize
+}
+
+// SetHistorySize sets the number of input lines remembered for )history.
+func (c *Config) SetHistorySize(size uint) {
+	c.init()
+	c.historySize = size
+}
+
+// OutputWidth returns the width, in characters, at which printed
+// vectors and matrices are wrapped. Zero means no wrapping.
+func (c *Config) OutputWidth() int {
+	c.init()
+	return c.outputWidth
+}
+
+// SetOutputWidth sets the width at which printed values are wrapped.
+func (c *Config) SetOutputWidth(width int) {
+	c.init()
+	c.outputWidth = width
+}
+
+// DateFormat returns the Go time layout used to print times. If it is
+// empty, times are printed in RFC 3339 format.
+func (c *Config) DateFormat() string {
+	return c.dateFormat
+}
+
+// SetDateFormat sets the Go time layout used to print times.
+func (c *Config) SetDateFormat(layout string) {
+	c.init()
+	c.dateFormat = layout
+}
+
+// Grouping returns the separator printed between groups of digits in
+// decimal integers, and the number of digits in each group.
+// An empty separator means digits are not grouped.
+func (c *Config) Grouping() (sep string, size int) {
+	c.init()
+	return c.groupSep, c.groupSize
+}
+
+// SetGrouping sets the separator and size of digit groups in decimal
+// integer output. An empty separator disables grouping.
+func (c *Config) SetGrouping(sep string, size int) {
+	c.init()
+	c.groupSep = sep
+	c.groupSize = size
+}
+
 // CPUTime returns the duration of the last interactive operation.
 func (c *Config) CPUTime() time.Duration {
 	c.init()
@@ -258,11 +572,80 @@ func (c *Config) SetCPUTime(d time.Duration) {
 	c.cpuTime = d
 }
 
+// Interrupt asks for the current evaluation to stop and reports whether
+// there is one; if not, the interrupt is ignored. It may be called
+// concurrently with evaluation, for instance from a signal handler.
+func (c *Config) Interrupt() bool {
+	if atomic.LoadInt32(&c.evaluating) == 0 {
+		return false
+	}
+	atomic.StoreInt32(&c.interrupt, 1)
+	return true
+}
+
+// StartEvaluation records that an evaluation is running, so Interrupt
+// will stop it. The returned function records that it is done and must
+// be called when the evaluation finishes, even if it fails.
+func (c *Config) StartEvaluation() (done func()) {
+	atomic.StoreInt32(&c.evaluating, 1)
+	return func() {
+		atomic.StoreInt32(&c.evaluating, 0)
+	}
+}
+
+// Interrupted reports whether Interrupt has been called since the last
+// call to Interrupted. It may be called concurrently with Interrupt.
+func (c *Config) Interrupted() bool {
+	return atomic.LoadInt32(&c.interrupt) != 0 && atomic.SwapInt32(&c.interrupt, 0) != 0
+}
+
+// Timeout returns the longest time an evaluation may take.
+// Zero means there is no limit.
+func (c *Config) Timeout() time.Duration {
+	return c.timeout
+}
+
+// SetTimeout sets the longest time an evaluation may take.
+func (c *Config) SetTimeout(d time.Duration) {
+	c.init()
+	c.timeout = d
+}
+
+// StartTimeout begins timing an evaluation. Once the timeout has
+// elapsed, TimedOut reports true. The returned function stops the timer
+// and must be called when the evaluation is done, even if it fails.
+func (c *Config) StartTimeout() (stop func()) {
+	if c.timeout <= 0 {
+		return func() {}
+	}
+	// The timer may fire even as it is being stopped, so it records
+	// which evaluation ran out of time; a later one is unaffected.
+	timing := atomic.AddInt32(&c.timing, 1)
+	t := time.AfterFunc(c.timeout, func() {
+		atomic.StoreInt32(&c.timedOut, timing)
+	})
+	return func() {
+		t.Stop()
+		atomic.AddInt32(&c.timing, 1)
+	}
+}
+
+// TimedOut reports whether the evaluation being timed has run longer
+// than the timeout.
+func (c *Config) TimedOut() bool {
+	timing := atomic.LoadInt32(&c.timing)
+	return timing != 0 && atomic.LoadInt32(&c.timedOut) == timing
+}
+
 // PrintCPUTime returns a nicely formatted version of the CPU time, with 3 decimal
 // places in whatever unit best fits. The default String method for Duration prints too
 // many decimals.
 func (c *Config) PrintCPUTime() string {
-	d := c.cpuTime
+	return FormatDuration(c.cpuTime)
+}
+
+// FormatDuration returns d formatted in the style of PrintCPUTime.
+func FormatDuration(d time.Duration) string {
 	switch {
 	case d > time.Minute:
 		m := int(d.Minutes())
@@ -4,11 +4,12 @@
 
 // +build ignore
 
-// Demo is a demo driver for ivy. It takes no arguments.
+// Demo is a demo driver for ivy. It takes an optional argument
+// naming the script to run; the default is demo.ivy.
 // Install ivy into your $PATH, then build demo and run
 // it in the demo directory.
 //
-// It reads each line of the demo.ivy file, waiting for a
+// It reads each line of the script, waiting for a
 // newline on standard input to proceed. After receiving
 // a newline, it prints the next line of demo.ivy and also
 // feeds it to a single running ivy instance, which prints
@@ -29,11 +30,15 @@ import (
 
 func main() {
 	log.SetFlags(0)
-	if len(os.Args) != 1 {
-		log.Fatal("Usage: cd ivy/demo; go build demo.go; ./demo\n")
+	if len(os.Args) > 2 {
+		log.Fatal("Usage: cd ivy/demo; go build demo.go; ./demo [file.ivy]\n")
 	}
 	log.SetPrefix("demo: ")
-	text, err := ioutil.ReadFile(pathTo("demo.ivy"))
+	script := "demo.ivy"
+	if len(os.Args) == 2 {
+		script = os.Args[1]
+	}
+	text, err := ioutil.ReadFile(pathTo(script))
 	ck(err)
 	cmd := exec.Command("ivy")
 	cmd.Stdout = os.Stdout
@@ -14,7 +14,15 @@ progress.
 Unlike APL, the input is ASCII and the results are exact (but see the next paragraph).
 It uses exact rational arithmetic so it can handle arbitrary precision. Values to be
 input may be integers (3, -1), rationals (1/3, -45/67) or floating point values (1e3,
--1.5 (representing 1000 and -3/2)).
+-1.5 (representing 1000 and -3/2)). Underscores may separate digits
+for readability, as in 1_000_000 or 0xFF_FF. Complex numbers are written with a j
+separating the real and imaginary parts: 3j4 is 3+4i, and 1j-1/2 is 1-i/2.
+A complex number with a zero imaginary part is real, so 5j0 is just 5. Complex
+values print in the form they are written, in the output base, so they can be read
+back. In bases of 20 and above, j is a numeral and complex numbers cannot be written.
+The parts may be of any real type. Arithmetic, comparison for equality, abs,
+real, imag, + (conjugate), and ** (exponential, and powers with an integer
+exponent) support complex values.
 
 Some functions such as sqrt are irrational. When ivy evaluates an irrational
 function, the result is stored in a high-precision floating-point number (default
@@ -39,9 +47,19 @@ selects multiple elements: x[1 2] creates a new item from x[1] and x[2].
 Only a subset of APL's functionality is implemented, but the intention is to
 have most numerical operations supported eventually.
 
+Comments run from # to the end of the line. A block comment begins with /* and
+ends with an asterisk followed by a slash; it may span several lines and may be
+nested.
+
 Semicolons separate multiple statements on a line. Variables are alphanumeric and are
 assigned with the = operator. Assignment is an expression.
 
+A line that ends with an operator or an opening parenthesis or bracket is
+incomplete and continues on the next line, so
+	x = 1 2 3 +
+	    4 5 6
+sets x to 5 7 9. A blank line abandons an incomplete line.
+
 After each successful expression evaluation, the result is stored in the variable
 called _ (underscore) so it can be used in the next expression.
 
@@ -52,34 +70,89 @@ Unary operators
 
 	Name              APL   Ivy     Meaning
 	Roll              ?B    ?       One integer selected randomly from the first B integers
+	Random                  rand    Uniform random floats in [0, 1); B is a count or a shape
+	Random normal           randn   Random floats from the standard normal distribution; ditto
 	Ceiling           ⌈B    ceil    Least integer greater than or equal to B
 	Floor             ⌊B    floor   Greatest integer less than or equal to B
+	Round                   round   Nearest integer to B; halves round to even, so round 2.5 is 2
 	Shape             ⍴B    rho     Number of components in each dimension of B
 	Not               ∼B    not     Logical: not 1 is 0, not 0 is 1
 	Absolute value    ∣B    abs     Magnitude of B
+	Real part               real    Real part of B
+	Imaginary part          imag    Imaginary part of B; 0 if B is real
 	Index generator   ⍳B    iota    Vector of the first B integers
+	Where             ⍸B    where   Indices of the 1s in B; a count n repeats its index n times;
+	                                for a matrix, a matrix of their coordinates
 	Exponential       ⋆B    **      e to the B power
 	Negation          −B    -       Changes sign of B
-	Identity          +B    +       No change to B
+	Conjugate         +B    +       Complex conjugate of B; no change to real B
 	Signum            ×B    sgn     ¯1 if B<0; 0 if B=0; 1 if B>0
 	Reciprocal        ÷B    /       1 divided by B
 	Ravel             ,B    ,       Reshapes B into a vector
-	Matrix inverse    ⌹B            Inverse of matrix B
+	Unique            ∪B    unique  Distinct elements (rows of a matrix) of B, in order of appearance
+	Tally                   tally   2-row matrix of the distinct elements of B and their counts
+	Enclose           ⊂B    enclose Encloses array B in a scalar box
+	Disclose          ⊃B    disclose Contents of box B; a vector of boxes becomes a matrix
+	Depth             ≡B    depth   Levels of nesting in B; 0 for a simple scalar
+	First             ↑B    first   First item (element or row) of B; zeros if B is empty
+	Last                    last    Last item of B
+	Head                    head    B without its last item; ¯1↓B
+	Tail                    tail    B without its first item; 1↓B
+	Matrix inverse    ⌹B    inv     Inverse of matrix B
 	Pi times          ○B            Multiply by π
 	Logarithm         ⍟B    log     Natural logarithm of B
 	Reversal          ⌽B    rot     Reverse elements of B along last axis
 	Reversal          ⊖B    flip    Reverse elements of B along first axis
 	Grade up          ⍋B    up      Indices of B which will arrange B in ascending order
 	Grade down        ⍒B    down    Indices of B which will arrange B in descending order
+	                                The sort is stable; a matrix is sorted by rows
+	Sort                    sort    B sorted into ascending order; B[up B]
+	Sort down               sortdown B sorted into descending order; B[down B]
 	Execute           ⍎B    ivy     Execute an APL (ivy) expression
 	Monadic format    ⍕B    text    A character representation of B
+	Upper case              upper   Chars of B in upper case
+	Lower case              lower   Chars of B in lower case
+	Trim                    trim    B without leading and trailing white space; in a char matrix, per row
+	Shell command           sh      Standard output of the shell command B; requires ) shell on
+	Environment             getenv  Value of the environment variable named B; empty if unset
+	Read CSV                csv     Matrix of the fields of the CSV file named B; non-numbers are enclosed
+	JSON                    json    JSON text of B; matrices become nested arrays, boxes their contents
+	From JSON               unjson  Value of the JSON text B; arrays of equal arrays become matrices
+	                                Strings are not arrays, so a char matrix returns as enclosed strings
+	Base64                  base64  Base64 encoding of the string or bytes (integers 0 to 255) B
+	From base64             unbase64 String or, if not UTF-8, bytes encoded in base64 by B
+	Hex                     hex     Hexadecimal encoding of the string or bytes (integers 0 to 255) B
+	From hex                unhex   String or, if not UTF-8, bytes encoded in hexadecimal by B
+	Date                    date    Time written as the string B, or B seconds after 1970-01-01 UTC
+	Since                   since   Seconds from time B to now
+	Human time              humantime B seconds as days, hours, minutes and seconds, as in 1d 1h 1m 1s
 	Monadic transpose ⍉B    transp  Reverse the axes of B
+	Determinant             det     Determinant of square matrix B
 	Factorial         !B    !       Product of integers 1 to B
+	Gamma                   gamma   Gamma function; gamma B is !B-1 for integer B
+	Log gamma               lgamma  Natural logarithm of abs gamma B
+	Primality               isprime 1 if B is prime, 0 if not; probabilistic beyond 2**64
+	Next prime              nextprime Smallest prime greater than B
+	Factorization           factor  Prime factors of B in increasing order, with multiplicity
+	Mean                    mean    Arithmetic mean of B; exact for integers and rationals
+	Variance                var     Sample variance of B, dividing by one less than its length
+	Standard deviation      stddev  Square root of var B
+	Median                  median  Middle value of sorted B; mean of the middle two if even
+	                                For a matrix, these apply along the last axis
+	Continued fraction      cfrac   Continued-fraction terms of B; for a float, enough to equal B
+	From continued fraction fromcfrac Number whose continued fraction has the terms B
+	Roots                   roots   Roots of the polynomial with coefficients B, highest power first
 	Bitwise not             ^       Bitwise complement of B (integer only)
 	Square root       B⋆.5  sqrt    Square root of B.
 	Sine                    sin     sin(A); APL uses binary ○ (see below)
 	Cosine                  cos     cos(A); ditto
 	Tangent                 tan     tan(A); ditto
+	Hyperbolic sine         sinh    sinh(B)
+	Hyperbolic cosine       cosh    cosh(B)
+	Hyperbolic tangent      tanh    tanh(B)
+	Inverse sinh            asinh   asinh(B)
+	Inverse cosh            acosh   acosh(B); B must be at least 1
+	Inverse tanh            atanh   atanh(B); abs B must be less than 1
 
 Binary operators
 
@@ -91,6 +164,7 @@ Binary operators
 	                            div     A divided by B (Euclidean)
 	                            idiv    A divided by B (Go)
 	Exponentiation        A⋆B   **      A raised to the B power
+	Root                        root    The A'th root of B, A a positive integer; exact for perfect powers
 	Circle                A○B           Trigonometric functions of B selected by A
 	                                    A=1: sin(B) A=2: cos(B) A=3: tan(B); ¯A for inverse
 	                            sin     sin(B); ivy uses traditional name.
@@ -101,34 +175,76 @@ Binary operators
 	                            atan    arctan(B); ivy uses traditional name.
 	Deal                  A?B   ?       A distinct integers selected randomly from the first B integers
 	Membership            A∈B   in      1 for elements of A present in B; 0 where not.
+	Count                       count   Number of times each element of A occurs in B
+	Union                 A∪B   union   Distinct elements of A and B, in order of appearance
+	Intersection          A∩B   intersect Distinct elements of A that are also in B
+	Without               A~B   diff    Distinct elements of A that are not in B
 	Maximum               A⌈B   max     The greater value of A or B
 	Minimum               A⌊B   min     The smaller value of A or B
+	Round to multiple           round   Multiple of A nearest B, halves to even: 0.01 round 3.14159 is 3.14
+	Ceiling to multiple         ceil    Least multiple of A greater than or equal to B
+	Floor to multiple           floor   Greatest multiple of A less than or equal to B
+	Approximation               approx  Nearest fraction to B with denominator at most A
 	Reshape               A⍴B   rho     Array of shape A with data B
-	Take                  A↑B   take    Select the first (or last) A elements of B according to ×A
-	Drop                  A↓B   drop    Remove the first (or last) A elements of B according to ×A
+	                                    repeated as needed; if B is empty, the data are zeros
+	Take                  A↑B   take    Select the first (or last) A elements of B according to ×A;
+	                                    taking too many pads with zeros (spaces for chars);
+	                                    if A is a vector, its elements apply to successive axes of B
+	Drop                  A↓B   drop    Remove the first (or last) A elements of B according to ×A;
+	                                    if A is a vector, its elements apply to successive axes of B
 	Decode                A⊥B   decode  Value of a polynomial whose coefficients are B at A
+	Polynomial value            polyval Value at B of the polynomial with coefficients A,
+	                                    highest power first
 	Encode                A⊤B   encode  Base-A representation of the value of B
 	Residue               A∣B           B modulo A
 	                            mod     A modulo B (Euclidean)
 	                            imod    A modulo B (Go)
+	GCD                   A∨B   gcd     Greatest common divisor of A and B
+	LCM                   A∧B   lcm     Least common multiple of A and B
+	Modular power               powmod  B raised to the power A[1], modulo A[2]; A[1] may be negative
 	Catenation            A,B   ,       Elements of B appended to the elements of A
 	Expansion             A\B   fill    Insert zeros (or blanks) in B corresponding to zeros in A
 	                                    In ivy: abs(A) gives count, A <= 0 inserts zero (or blank)
+	                            \       Same as fill; both apply to the last axis of a matrix
 	Compression           A/B   sel     Select elements in B corresponding to ones in A
-	                                    In ivy: abs(A) gives count, A <= 0 inserts zero
+	                                    In ivy: abs(A) gives count, A <= 0 inserts zero;
+	                                    applies to the last axis of a matrix
+	Selection                   select  Elements of the first of B where A is 1, of the second where 0;
+	                                    B is a matrix with 2 rows or 2 boxes; a branch may be a scalar
 	Index of              A⍳B   iota    The location (index) of B in A; 1+⌈/⍳⍴A if not found
 	                                    In ivy: origin-1 if not found (i.e. 0 if one-indexed)
-	Matrix divide         A⌹B           Solution to system of linear equations Ax = B
+	Matrix divide         A⌹B   inv     Solution to system of linear equations Ax = B;
+	                                    least-squares solution if A has more rows than columns
+	Dot product                 dot     Same as A+.×B
+	Cross product               cross   Cross product of the 3-vectors A and B
+	Kronecker product           kron    Block array of each element of A times B;
+	                                    each axis is the product of the axes of A and B
+	Matrix power                mpow    Square matrix A multiplied by itself B times;
+	                                    the identity if B is 0, a power of the inverse if B < 0
+	Grade up              A⍋B   up      Indices that sort the chars of B in the order of alphabet A;
+	                                    chars not in A sort last
+	Grade down            A⍒B   down    Indices that sort the chars of B in descending order of A
 	Rotation              A⌽B   rot     The elements of B are rotated A positions left
 	Rotation              A⊖B   flip    The elements of B are rotated A positions along the first axis
 	Logarithm             A⍟B   log     Logarithm of B to base A
+	                                    exact if A and B are exact and B is an integral power of A
 	Dyadic format         A⍕B   text    Format B into a character matrix according to A
 	                                    A is the textual format (see format special command);
 	                                    otherwise result depends on length of A:
 	                                    1 gives decimal count, 2 gives width and decimal count,
 	                                    3 gives width, decimal count, and style ('d', 'e', 'f', etc.).
-	General transpose     A⍉B           The axes of B are ordered by A
-	Combinations          A!B   !       Number of combinations of B taken A at a time
+	                                    If B is a time, A is a Go time layout such as '2006-01-02'.
+	Set environment             setenv  Set the environment variable named A to the string B; returns B
+	Match                       match   1 if the regular expression A matches the string B, 0 if not;
+	                                    a char matrix or vector of enclosed strings B gives a vector
+	Find                        find    Enclosed substrings of B matched by the regular expression A;
+	                                    with capture groups, a matrix of the groups of each match
+	Write CSV                   csv     Write the rows of matrix A to the CSV file named B;
+	                                    returns the number of rows written
+	General transpose     A⍉B   transp  The axes of B are ordered by A: axis i of B becomes axis A[i];
+	                                    repeated axes take the diagonal, as in 1 1 transp B
+	Combinations          A!B   !       Number of combinations of B taken A at a time;
+	                                    uses gamma function for non-integer A or B
 	Less than             A<B   <       Comparison: 1 if true, 0 if false
 	Less than or equal    A≤B   <=      Comparison: 1 if true, 0 if false
 	Equal                 A=B   ==      Comparison: 1 if true, 0 if false
@@ -144,18 +260,33 @@ Binary operators
 	Bitwise or                  |       Bitwise A or B (integer only)
 	Bitwise xor                 ^       Bitwise A exclusive or B (integer only)
 	Left shift                  <<      A shifted left B bits (integer only)
+	                            shiftl  Same as <<
 	Right Shift                 >>      A shifted right B bits (integer only)
+	                            shiftr  Same as >>
 
 Operators and axis indicator
 
 	Name                APL  Ivy  APL Example  Ivy Example  Meaning (of example)
 	Reduce (last axis)  /    /    +/B          +/B          Sum across B
-	Reduce (first axis) ⌿         +⌿B                       Sum down B
+	                              2+/B         2 +/B        N-wise: sums of adjacent pairs in B;
+	                                                    with a negative count, each window is taken backward
+	Reduce (first axis) ⌿    /%   +⌿B          +/%B         Sum down B
 	Scan (last axis)    \    \    +\B          +\B          Running sum across B
-	Scan (first axis)   ⍀         +⍀B                       Running sum down B
+	Scan (first axis)   ⍀    \%   +⍀B          +\%B         Running sum down B
 	Inner product       .    .    A+.×B        A +.* B      Matrix product of A and B
 	Outer product       ∘.   o.   A∘.×B        A o.* B      Outer product of A and B
 	                                                    (lower case o; may need preceding space)
+	                                                    (either operator may be user-defined, as in A min.+ B)
+	Range sum                sum  +/f⍳N        f sum 1 N    Sum of f applied to each integer from 1 to N
+	Range product            prod ×/f⍳N        f prod 1 N   Product of the same
+	                                                    (f is a unary operator; the range is never materialized)
+	Axis                [K]  [    ⌽[1]B        rot[1] B     Reverse B along axis 1
+	                              1⌽[1]B       1 rot[1] B   Rotate B along axis 1
+	                              1 0/[1]B     1 0 sel[1] B Select row 1 of B
+	                              1 0 1\[1]B   1 0 1 \[1] B Insert an empty row in B
+	                              A,[1]B       A ,[1] B     Join A and B along axis 1
+	                              A,[0.5]B     A ,[0.5] B   Laminate A and B along a new first axis
+	                                                    (rot, flip, sel, fill, \ and , only; the axis counts from the origin)
 
 Type-converting operations
 
@@ -170,6 +301,34 @@ The constants e (base of natural logarithms) and pi (π) are pre-defined to high
 precision, about 3000 decimal digits truncated according to the floating point
 precision setting.
 
+Reading data
+
+The name readv, unless it is a variable, reads the rest of the standard input
+and returns the whitespace-separated words in it: a vector if they are all
+numbers, otherwise a char matrix with one word per row. It suits pipelines:
+
+	cat data | ivy -e 'mean readv'
+
+Times
+
+A time is a scalar holding an instant. The name now, unless it is a variable,
+is the current time, and date makes a time from a string such as '2024-01-15'
+or '2024-01-15 10:30', or from a number of seconds since 1970-01-01 UTC.
+Adding or subtracting a number of seconds to a time yields a time, subtracting
+two times yields the seconds between them, and times compare in order:
+
+	((date '2024-03-01') - date '2024-01-15') / 86400
+	46
+
+The unary since gives the seconds elapsed since a time, and humantime writes a
+number of seconds readably:
+
+	humantime 90061
+	1d 1h 1m 1s
+
+Times print according to ) dateformat; '2006-01-02' text t formats the time t
+with any Go time layout.
+
 Character data
 
 Strings are vectors of "chars", which are Unicode code points (not bytes).
@@ -185,13 +344,32 @@ Unlike in Go, a string in ivy comprises code points, not bytes; as such it can
 contain only valid Unicode values. Thus in ivy "\x80" is illegal, although it is
 a legal one-byte string in Go.
 
+Interpreted strings accept Go's escape sequences, such as \n, \t, \\, \",
+\xHH, \uHHHH and \UHHHHHHHH; an invalid one is an error.
+
 Strings can be printed. If a vector contains only chars, it is printed without
-spaces between them.
+spaces between them. Printing shows the characters themselves, so "a\tb" prints
+with a tab, but operator listings and ) save re-escape control characters.
 
 Chars have restricted operations. Printing, comparison, indexing and so on are
 legal but arithmetic is not, and chars cannot be converted automatically into other
 singleton values (ints, floats, and so on). The unary operators char and code
-enable transcoding between integer and char values.
+enable transcoding between integer and char values. They apply to each element
+of an array, so code 'ab' is 97 98 and char 97 98 is 'ab'; char rejects
+non-integers, surrogates and values beyond the Unicode range.
+
+Nested arrays
+
+The unary operator enclose wraps an array in a box, which is a scalar and so
+can be an element of another array. Thus
+	(enclose 1 2 3), (enclose 4 5), 6
+is a three-element vector whose first two elements are boxes. Boxes print with
+a frame drawn around their contents. Enclosing a simple scalar has no effect.
+The operator disclose undoes enclose; applied to a vector of boxes, it returns
+the matrix whose rows are the contents, padded with zeros or spaces as by take
+to the length of the longest. The operator depth reports the level of nesting:
+0 for a simple scalar, 1 for an array of simple scalars, and one more for each
+level of enclosure.
 
 User-defined operators
 
@@ -245,10 +423,28 @@ To declare an operator but not define it, omit the equals sign and what follows.
 	bar 3
 	result: 1/3
 
+To have an operator remember its results, write "memo" after the 'op'.
+A later call with the same arguments returns the remembered result without
+evaluating the body again. Each operator remembers up to 10000 results.
+They are forgotten when a global variable they used is assigned, when an
+operator is defined, and after a special command such as )origin, which may
+change the configuration. A result that uses a local variable of a calling
+operator is not remembered.
+	op memo fib n = ((2 2 rho 1 1 1 0) mpow n)[1][2]
+	fib 100
+	result: 354224848179261915075
+
 Within a user-defined operator, identifiers are local to the invocation unless
 they are undefined in the operator but defined globally, in which case they refer to
 the global variable. A mechanism to declare locals may come later.
 
+An error during evaluation names the operator that failed and the types and
+shapes of its operands, and, when reading a file, the line on which the
+operator appears, which for a user-defined operator is in its definition:
+
+	1 2 + 3 4 5
+	+: length mismatch: 2 3 (int vector 2 + int vector 3)
+
 Special commands
 
 Ivy accepts a number of special commands, introduced by a right paren
@@ -256,61 +452,191 @@ at the beginning of the line. Most report the current value if a new value
 is not specified. For these commands, numbers are always read and printed
 base 10 and must be non-negative on input.
 
+When ivy is run interactively, an interrupt (typically control-C) stops the
+current evaluation with the error "interrupted" and returns to the prompt,
+leaving variables and operators as they were.
+
 	) help
 		Describe the special commands. Run )help <topic> to learn more
 		about a topic, )help <op> to learn more about an operator.
 	) base 0
 		Set the number base for input and output. The commands ibase and
 		obase control setting of the base for input and output alone,
-		respectively.  Base 0 allows C-style input: decimal, with 037 being
-		octal and 0x10 being hexadecimal. If the base is greater than 10,
+		respectively.  Base 0 allows C-style input: decimal, with 037 or
+		0o37 being octal, 0x10 being hexadecimal and 0b101 being binary.
+		In base 0, such numbers may have a fraction and a binary exponent,
+		as in 0x1.8p3. If the base is greater than 10,
 		any identifier formed from valid numerals in the base system, such
 		as abe for base 16, is taken to be a number. TODO: To output
 		large integers and rationals, base must be one of 0 2 8 10 16.
-		Floats are always printed base 10.
+		If the output base is 2, 8 or 16, floats are printed exactly in
+		that base with a binary exponent, like C's %a: 1.5 prints as
+		0x1.8p0 in base 16. Otherwise floats are printed base 10.
+		The argument "default" restores the default base, 0.
+	) cd "dir"
+		Change the working directory, used to resolve relative file names
+		in ) get and ) save. If no directory is specified, change to the
+		user's home directory.
+	) clear
+		Delete all variables and user-defined operators, and restore the
+		base, formats and origin to their defaults. The prompt and other
+		settings are unchanged. With an argument of "vars" or "ops",
+		delete only the variables or only the operators.
+	) color on|off
+		Print values and error messages in color, using ANSI escape
+		sequences, when the output is a terminal. The default is off.
+		With no argument, print the setting.
 	) cpu
 		Print the duration of the last interactive calculation.
 	) debug name 0|1
 		Toggle or set the named debugging flag. With no argument, lists
-		the settings.
-	) demo
+		the settings. The parse flag prints the parse tree of each
+		expression before it is evaluated, fully parenthesized so the
+		grouping is explicit. The time flag prints, for each operator applied
+		outside user-defined operators, the time it took and the type
+		and shape of its result.
+	) dateformat ""
+		Set the Go time layout, such as "2006-01-02 15:04", used to print
+		times. The default, "", prints times in RFC 3339 format. With no
+		argument, print the layout.
+	) demo name
 		Run a line-by-line interactive demo. Requires a Go installation.
+		If a name is given, run the script demo/name.ivy, or the program
+		demo/name.go if there is one, instead of the standard demo.
 	) format ""
 		Set the format for printing values. If empty, the output is printed
 		using the output base. If non-empty, the format determines the
 		base used in printing. The format is in the style of golang.org/pkg/fmt.
 		For floating-point formats, flags and width are ignored.
+		The argument "default" restores the default, empty format.
+	) format int|rat|float ""
+		Set the format for printing values of just one type: integers,
+		rationals or floating-point numbers. A type's own format takes
+		precedence over the general one, which it otherwise inherits.
+		With no format, print the format for the type; "default" removes it.
+		With no arguments, ) format also lists the formats for types.
+	) format sci|fixed|auto
+		Set the notation for floating-point values printed with the default
+		format or a %g format: always scientific (1.5e+02), always fixed
+		(150), or chosen automatically as %g does, the default. The form
+		) format auto low high uses fixed notation when the decimal exponent
+		is at least low and less than high. Huge values always use scientific
+		notation.
+	) format rational on|off
+		If off, print rationals as floating-point numbers, rounded to the
+		floating-point precision, rather than exactly. Their values are
+		unchanged and computation with them remains exact. The default is
+		on. With no argument, print the setting.
 	) get "save.ivy"
 		Read input from the named file; return to interactive execution
 		afterwards. If no file is specified, read from "save.ivy".
+		If the name begins with http:// or https://, the file is
+		fetched from that URL.
 		(Unimplemented on mobile.)
+	) grouping "," 3
+		Print decimal integers with the separator between each group of
+		digits, as in 1,000,000; the group size defaults to 3. Numbers
+		written with the same grouping are accepted on input. Use
+		)grouping default to turn grouping off.
+	) history n
+		If n is absent, list the most recent input lines, numbered.
+		Otherwise, run input line n again. The command ) history size 100
+		sets how many lines are remembered; 100 is the default.
+	) intbits 0
+		Make integer arithmetic wrap around, as in machine arithmetic:
+		integer results of operators such as +, *, ** and << are reduced
+		to two's-complement integers of this many bits, so with )intbits
+		32, 0x7fffffff + 1 is -2147483648. If intbits is 0, the default,
+		integers have arbitrary precision. Otherwise it must be between
+		2 and 64.
 	) maxbits 1e6
 		To avoid consuming too much memory, if an integer result would
 		require more than this many bits to store, abort the calculation.
 		If maxbits is 0, there is no limit; the default is 1e6.
+	) maxdepth 1e4
+		To stop runaway recursion, if calls of user-defined operators
+		nest more than this deep, abort the calculation. If maxdepth
+		is 0, there is no limit; the default is 1e4.
 	) maxdigits 1e4
 		To avoid overwhelming amounts of output, if an integer has more
 		than this many digits, print it using the defined floating-point
 		format. If maxdigits is 0, integers are always printed as integers.
+	) nan on|off
+		If on, arithmetic that has no finite result yields the special
+		floating-point values Inf, -Inf and NaN, as in IEEE arithmetic,
+		rather than an error: 1/0 is Inf, 0/0 and log -1 are NaN. NaN
+		propagates through elementwise operations and is unequal to
+		everything, itself included. The default is off.
+		With no argument, print the setting.
 	) op X
 		If X is absent, list all user-defined operators. Otherwise,
 		show the definition of the user-defined operator X. Inside the
 		definition, numbers are always shown base 10, ignoring the ibase
 		and obase.
 	) origin 1
-		Set the origin for indexing a vector or matrix.
+		Set the origin for indexing a vector or matrix. The argument
+		"default" restores the default origin, 1.
+	) parallel on|off
+		If on, reductions such as +/ of large vectors of integers or
+		rationals by associative operators are split across the available
+		CPUs. The result is the same either way. The default is on.
+		With no argument, print the setting.
 	) prec 256
 		Set the precision (mantissa length) for floating-point values.
 		The value is in bits. The exponent always has 32 bits.
 	) prompt ""
-		Set the interactive prompt.
+		Set the interactive prompt. Each time it is printed, %n in the
+		prompt becomes the number of the next input line, %b the input
+		base and %% a percent sign, so ) prompt "%n> " numbers the lines.
+		With no argument, print the prompt.
+	) prompt2 "    "
+		Set the prompt for the continuation of an incomplete line. The
+		default is four spaces. With no argument, print the prompt.
+	) pwd
+		Print the working directory.
 	) save "save.ivy"
 		Write definitions of user-defined operators and variables to the
 		named file, as ivy textual source. If no file is specified, save to
-		"save.ivy".
+		"save.ivy". If the file name is followed by a list of names, save
+		only those operators and variables, plus any operators they use.
+		The configuration is then not saved. If the first argument is -a,
+		append to the file rather than replacing it.
 		(Unimplemented on mobile.)
 	) seed 0
-		Set the seed for the ? operator.
+		Set the seed for the unary (roll) and binary (deal) ? operators.
+		Setting the same seed again repeats the same sequence of values.
+	) shell off
+		Allow the sh operator to run shell commands. Because a script can
+		then do anything the user can, the default is off.
+	) time expression
+		Evaluate the expression and print the wall-clock and CPU time
+		it took, followed by the result.
+	) timeout 0
+		If an evaluation takes longer than this many seconds, which
+		may be fractional, abort it. This guards against runaway
+		scripts. If timeout is 0, there is no limit; the default is 0.
+	) tolerance 0
+		Set the comparison tolerance, as in APL's ⎕CT. Rationals and floats
+		x and y compare equal, for == != < <= > >= and the operators built
+		on them, if |x-y| is at most the tolerance times the larger of |x|
+		and |y|. The default, 0, makes comparisons exact. With no argument,
+		print the setting.
+	) undef X
+		Delete the user-defined operator X. If X is defined as both a
+		unary and a binary operator, both are deleted; to delete only
+		one, follow the name with "unary" or "binary".
+	) vars X
+		If X is absent, list all variables, with the type and shape of
+		each. Scalar values are also printed. Otherwise, describe only
+		the variable X.
+	) warnoverflow off
+		Print a warning to the error output whenever the result of an
+		integer +, - or * does not fit in a small integer and is promoted
+		to a big integer. The result itself is unchanged.
+	) width 0
+		Wrap printed vectors and matrices so no line is longer than this
+		many characters. Lines break between elements, and continuation
+		lines are indented. If width is 0, output is not wrapped.
 
 */
 package main
@@ -34,6 +34,9 @@ type Context struct {
 	Defs []OpDef
 	// Names of variables declared in the currently-being-parsed function.
 	variables []string
+	// memoCalls holds the invocations of memoized operators in progress,
+	// so Lookup can note what else their results depend on.
+	memoCalls []*memoCall
 }
 
 // NewContext returns a new execution context: the stack and variables,
@@ -65,12 +68,48 @@ func (c *Context) Lookup(name string) value.Value {
 	for i := len(c.Stack) - 1; i >= 0; i-- {
 		v := c.Stack[i][name]
 		if v != nil {
+			if len(c.memoCalls) > 0 {
+				c.noteLookup(name, i)
+			}
 			return v
 		}
 	}
 	return nil
 }
 
+// noteLookup records, for the memoized operators being evaluated,
+// that the variable was found in stack frame i.
+func (c *Context) noteLookup(name string, i int) {
+	for _, call := range c.memoCalls {
+		switch {
+		case i >= call.frame:
+			// The operator's own variable.
+		case i == 0:
+			call.fn.depend(name)
+		default:
+			call.local = true
+		}
+	}
+}
+
+// startMemo notes the start of an invocation of fn, whose frame has
+// just been pushed. It returns nil if fn is not memoized.
+func (c *Context) startMemo(fn *Function) *memoCall {
+	if !fn.Memo {
+		return nil
+	}
+	call := &memoCall{fn: fn, frame: len(c.Stack) - 1}
+	c.memoCalls = append(c.memoCalls, call)
+	return call
+}
+
+// endMemo is the dual of startMemo.
+func (c *Context) endMemo(call *memoCall) {
+	if call != nil {
+		c.memoCalls = c.memoCalls[:len(c.memoCalls)-1]
+	}
+}
+
 // assignLocal binds a value to the name in the current function.
 func (c *Context) assignLocal(name string, value value.Value) {
 	c.Stack[len(c.Stack)-1][name] = value
@@ -97,10 +136,25 @@ func (c *Context) Assign(name string, val value.Value) {
 	// Assign global variable.
 	c.noOp(name)
 	globals[name] = val
+	for _, fn := range c.UnaryFn {
+		if fn.deps[name] {
+			fn.forget()
+		}
+	}
+	for _, fn := range c.BinaryFn {
+		if fn.deps[name] {
+			fn.forget()
+		}
+	}
 }
 
 // push pushes a new frame onto the context stack.
+// Runaway recursion would overflow the Go stack, so it is an error
+// to exceed the configured maximum depth.
 func (c *Context) push() {
+	if max := c.config.MaxDepth(); max > 0 && uint(len(c.Stack)) > max {
+		value.Errorf("recursion too deep")
+	}
 	c.Stack = append(c.Stack, make(Symtab))
 }
 
@@ -121,14 +175,30 @@ func (c *Context) Eval(exprs []value.Expr) []value.Value {
 	return values
 }
 
+// checkInterrupt reports an error if the evaluation has been interrupted
+// or has run out of time. It is called for every operator applied, so
+// long computations stop promptly.
+func (c *Context) checkInterrupt() {
+	value.CheckInterrupt(c.config)
+}
+
 // EvalUnary evaluates a unary operator, including reductions and scans.
 func (c *Context) EvalUnary(op string, right value.Value) value.Value {
+	c.checkInterrupt()
 	if len(op) > 1 {
 		switch op[len(op)-1] {
 		case '/':
 			return value.Reduce(c, op[:len(op)-1], right)
 		case '\\':
 			return value.Scan(c, op[:len(op)-1], right)
+		case '%':
+			// Along the first axis.
+			switch op[len(op)-2] {
+			case '/':
+				return value.ReduceFirst(c, op[:len(op)-2], right)
+			case '\\':
+				return value.ScanFirst(c, op[:len(op)-2], right)
+			}
 		}
 	}
 	fn := c.Unary(op)
@@ -159,9 +229,14 @@ func (c *Context) UserDefined(op string, isBinary bool) bool {
 
 // EvalBinary evaluates a binary operator, including products.
 func (c *Context) EvalBinary(left value.Value, op string, right value.Value) value.Value {
+	c.checkInterrupt()
 	if strings.Contains(op, ".") {
 		return value.Product(c, left, op, right)
 	}
+	if len(op) > 1 && op[len(op)-1] == '/' {
+		// N-wise reduction.
+		return value.NwiseReduce(c, left, op[:len(op)-1], right)
+	}
 	fn := c.Binary(op)
 	if fn == nil {
 		value.Errorf("binary %q not implemented", op)
@@ -186,6 +261,7 @@ func (c *Context) Binary(op string) value.BinaryOp {
 // information used by the save method.
 func (c *Context) Define(fn *Function) {
 	c.noVar(fn.Name)
+	c.ForgetMemos()
 	if fn.IsBinary {
 		c.BinaryFn[fn.Name] = fn
 	} else {
@@ -211,6 +287,27 @@ func (c *Context) Define(fn *Function) {
 	c.Defs = append(c.Defs, OpDef{fn.Name, fn.IsBinary})
 }
 
+// Undefine removes the user-defined operator with the given name and
+// valence, reporting whether it was defined.
+func (c *Context) Undefine(name string, isBinary bool) bool {
+	fns := c.UnaryFn
+	if isBinary {
+		fns = c.BinaryFn
+	}
+	if fns[name] == nil {
+		return false
+	}
+	delete(fns, name)
+	c.ForgetMemos()
+	for i, def := range c.Defs {
+		if def.Name == name && def.IsBinary == isBinary {
+			c.Defs = append(c.Defs[:i], c.Defs[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
 // noVar guarantees that there is no global variable with that name,
 // preventing an op from being defined with the same name as a variable,
 // which could cause problems. A variable with value zero is considered to
@@ -244,6 +341,35 @@ func (c *Context) noOp(name string) {
 	value.Errorf("cannot define variable %s; it is an op", name)
 }
 
+// ClearVars removes all variables from the context, leaving only
+// the predefined constants.
+// Since the results of operators may depend on variables,
+// it also empties the caches of memoized operators.
+func (c *Context) ClearVars() {
+	c.Stack = []Symtab{make(Symtab)}
+	c.SetConstants()
+	c.ForgetMemos()
+}
+
+// ForgetMemos empties the caches of memoized operators. It is called
+// when something their results may depend on changes, such as the
+// definition of an operator or the configuration.
+func (c *Context) ForgetMemos() {
+	for _, fn := range c.UnaryFn {
+		fn.forget()
+	}
+	for _, fn := range c.BinaryFn {
+		fn.forget()
+	}
+}
+
+// ClearOps removes all user-defined operators from the context.
+func (c *Context) ClearOps() {
+	c.UnaryFn = make(map[string]*Function)
+	c.BinaryFn = make(map[string]*Function)
+	c.Defs = nil
+}
+
 // Declare makes the name a variable while parsing the next function.
 func (c *Context) Declare(name string) {
 	c.variables = append(c.variables, name)
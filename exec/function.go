@@ -6,6 +6,7 @@ package exec
 
 import (
 	"fmt"
+	"strings"
 
 	"robpike.io/ivy/value"
 )
@@ -17,14 +18,37 @@ type Function struct {
 	Left     string
 	Right    string
 	Body     []value.Expr
+	// Memo reports whether results are cached, keyed by the arguments.
+	Memo bool
+	memo map[string]value.Value
+	// deps holds the global variables the cached results depend on.
+	deps map[string]bool
 }
 
+// memoCall records an invocation of a memoized operator while it runs.
+// Variables found outside its own frame are not part of the key, so a
+// global one becomes a dependency whose assignment empties the cache,
+// and a local of a calling operator keeps the result from being cached.
+type memoCall struct {
+	fn    *Function
+	frame int  // Index of the invocation's frame in the stack.
+	local bool // Whether a local of a calling operator was used.
+}
+
+// maxMemo bounds the number of results cached by each operator.
+// When it is reached, the cache starts afresh.
+const maxMemo = 10000
+
 func (fn *Function) String() string {
 	left := ""
 	if fn.IsBinary {
 		left = fn.Left + " "
 	}
-	s := fmt.Sprintf("op %s%s %s =", left, fn.Name, fn.Right)
+	memo := ""
+	if fn.Memo {
+		memo = "memo "
+	}
+	s := fmt.Sprintf("op %s%s%s %s =", memo, left, fn.Name, fn.Right)
 	if len(fn.Body) == 1 {
 		return s + " " + fn.Body[0].ProgString()
 	}
@@ -34,14 +58,98 @@ func (fn *Function) String() string {
 	return s
 }
 
+// lookup returns the cached result for the key, if any.
+func (fn *Function) lookup(key string) (value.Value, bool) {
+	if !fn.Memo {
+		return nil, false
+	}
+	v, ok := fn.memo[key]
+	return v, ok
+}
+
+// remember caches the result v for the key, unless the call used
+// something other than its arguments and global variables.
+func (fn *Function) remember(key string, v value.Value, call *memoCall) {
+	if !fn.Memo || call.local {
+		return
+	}
+	if fn.memo == nil || len(fn.memo) >= maxMemo {
+		fn.memo = make(map[string]value.Value)
+	}
+	fn.memo[key] = v
+}
+
+// depend records that the cached results depend on the global variable.
+func (fn *Function) depend(name string) {
+	if fn.deps == nil {
+		fn.deps = make(map[string]bool)
+	}
+	fn.deps[name] = true
+}
+
+// forget empties the cache of results.
+func (fn *Function) forget() {
+	fn.memo = nil
+	fn.deps = nil
+}
+
+// memoKey returns a string that identifies the values by type and
+// content, for use as a key in the cache of results.
+func memoKey(vals ...value.Value) string {
+	var b strings.Builder
+	var key func(v value.Value)
+	key = func(v value.Value) {
+		switch v := v.(type) {
+		case value.Vector:
+			fmt.Fprintf(&b, "(%d", len(v))
+			for _, x := range v {
+				b.WriteByte(' ')
+				key(x)
+			}
+			b.WriteByte(')')
+		case value.Range:
+			// The same key as the equivalent vector.
+			fmt.Fprintf(&b, "(%d", v.Len())
+			for i := 0; i < v.Len(); i++ {
+				b.WriteByte(' ')
+				key(v.At(i))
+			}
+			b.WriteByte(')')
+		case *value.Matrix:
+			fmt.Fprintf(&b, "%v", v.Shape())
+			key(v.Data())
+		case value.Box:
+			b.WriteString("<")
+			key(v.Contents())
+			b.WriteString(">")
+		default:
+			fmt.Fprintf(&b, "%T:%s", v, v.ProgString())
+		}
+	}
+	for _, v := range vals {
+		key(v)
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
 func (fn *Function) EvalUnary(context value.Context, right value.Value) value.Value {
 	if fn.Body == nil {
 		value.Errorf("unary %q undefined", fn.Name)
 	}
+	var key string
+	if fn.Memo {
+		key = memoKey(right)
+		if v, ok := fn.lookup(key); ok {
+			return v
+		}
+	}
 	// It's known to be an exec.Context.
 	c := context.(*Context)
 	c.push()
 	defer c.pop()
+	call := c.startMemo(fn)
+	defer c.endMemo(call)
 	c.assignLocal(fn.Right, right)
 	var v value.Value
 	for _, e := range fn.Body {
@@ -50,6 +158,7 @@ func (fn *Function) EvalUnary(context value.Context, right value.Value) value.Va
 	if v == nil {
 		value.Errorf("no value returned by %q", fn.Name)
 	}
+	fn.remember(key, v, call)
 	return v
 }
 
@@ -57,10 +166,19 @@ func (fn *Function) EvalBinary(context value.Context, left, right value.Value) v
 	if fn.Body == nil {
 		value.Errorf("binary %q undefined", fn.Name)
 	}
+	var key string
+	if fn.Memo {
+		key = memoKey(left, right)
+		if v, ok := fn.lookup(key); ok {
+			return v
+		}
+	}
 	// It's known to be an exec.Context.
 	c := context.(*Context)
 	c.push()
 	defer c.pop()
+	call := c.startMemo(fn)
+	defer c.endMemo(call)
 	c.assignLocal(fn.Left, left)
 	c.assignLocal(fn.Right, right)
 	var v value.Value
@@ -70,5 +188,6 @@ func (fn *Function) EvalBinary(context value.Context, left, right value.Value) v
 	if v == nil {
 		value.Errorf("no value returned by %q", fn.Name)
 	}
+	fn.remember(key, v, call)
 	return v
 }
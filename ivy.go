@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strings"
 
 	"robpike.io/ivy/config"
@@ -98,6 +99,15 @@ func main() {
 
 	scanner := scan.New(context, "<stdin>", bufio.NewReader(os.Stdin))
 	parser := parse.NewParser("<stdin>", scanner, context)
+
+	// An interrupt stops the current evaluation, not ivy.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		for range interrupts {
+			run.Interrupt(parser, &conf)
+		}
+	}()
 	for !run.Run(parser, context, true) {
 	}
 }
@@ -141,3 +141,52 @@ func getText(t *testing.T, fileName string, lineNum int, lines []string) (input,
 	}
 	return // Will return nil if no more tests exist.
 }
+
+// A range reduction should use less memory than the reduction
+// of the materialized vector it replaces.
+
+func BenchmarkRangeSum(b *testing.B) {
+	mobile.Reset()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		mobile.Eval("- sum 1 100000")
+	}
+}
+
+// Iota makes a lazy range. Reductions of it, and of simple arithmetic
+// on it, should be faster than those of the materialized vector,
+// made here by ravel.
+
+func benchmarkRange(b *testing.B, x, expr string) {
+	mobile.Reset()
+	mobile.Eval("x = " + x)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mobile.Eval(expr)
+	}
+}
+
+func BenchmarkReduceRange(b *testing.B) { benchmarkRange(b, "iota 100000", "-/x") }
+
+func BenchmarkReduceRangeMaterialized(b *testing.B) { benchmarkRange(b, ", iota 100000", "-/x") }
+
+func BenchmarkSumRange(b *testing.B) { benchmarkRange(b, "iota 100000", "+/ 3 * 1 + x") }
+
+func BenchmarkSumRangeMaterialized(b *testing.B) { benchmarkRange(b, ", iota 100000", "+/ 3 * 1 + x") }
+
+// Reducing a large vector of integers should be faster in parallel.
+
+func benchmarkSum(b *testing.B, parallel string) {
+	mobile.Reset()
+	mobile.Eval(")parallel " + parallel)
+	mobile.Eval("x = 1e7 rho 3 (2**70) 5")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mobile.Eval("+/x")
+	}
+}
+
+func BenchmarkSumParallel(b *testing.B) { benchmarkSum(b, "on") }
+
+func BenchmarkSumSerial(b *testing.B) { benchmarkSum(b, "off") }
@@ -94,12 +94,32 @@ func (d *Demo) Next() (result string, err error) {
 // Reset clears all state to the initial value.
 func Reset() {
 	conf.SetFormat("")
+	for _, typ := range config.FormatTypes {
+		conf.SetTypeFormat(typ, "")
+	}
+	conf.SetRationalDecimal(false)
+	conf.SetColor(false)
+	conf.SetNaN(false)
+	conf.SetTolerance(0)
+	conf.SetParallel(true)
+	conf.SetWarnOverflow(false)
+	conf.SetShell(false)
+	conf.SetTimeout(0)
+	conf.ResetNotation()
 	conf.SetMaxBits(1e9)
 	conf.SetMaxDigits(1e4)
+	conf.SetMaxDepth(1e4)
+	conf.SetIntBits(0)
 	conf.SetOrigin(1)
 	conf.SetPrompt("")
+	conf.SetPrompt2(config.DefaultPrompt2)
+	conf.SetInput(strings.NewReader(""))
 	conf.SetBase(0, 0)
 	conf.SetRandomSeed(0)
+	conf.SetHistorySize(100)
+	conf.SetOutputWidth(0)
+	conf.SetGrouping("", 0)
+	conf.SetDateFormat("")
 	context = exec.NewContext(&conf)
 }
 
@@ -5,12 +5,21 @@
 package mobile
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/parse"
+	"robpike.io/ivy/run"
+	"robpike.io/ivy/scan"
 )
 
 // We know ivy works. These just test that the wrapper works.
@@ -46,6 +55,11 @@ func TestEvalError(t *testing.T) {
 		{"'x", "unterminated character constant"},
 		{"1/0", "zero denominator in rational"},
 		{"1 / 0", "division by zero"},
+		{"1 / 0", " :1: /: division by zero (int / int)"},
+		{"1 2 + 3 4 5", "+: length mismatch: 2 3 (int vector 2 + int vector 3)"},
+		{"rot[3] 2 2 rho 1", "rot: axis 3 out of range for rank 2 (rot int matrix 2 2)"},
+		{"op f x = sqrt x / 0\nf 1 2", " :1: /: division by zero (int vector 2 / int)"},
+		{"1__0", "bad number syntax: misplaced underscore in 1__0"},
 	}
 	for _, test := range tests {
 		Reset()
@@ -60,6 +74,224 @@ func TestEvalError(t *testing.T) {
 	}
 }
 
+func TestWarnOverflow(t *testing.T) {
+	var tests = []struct {
+		input    string
+		warnings int
+	}{
+		{"2147483647 + 1", 1},
+		{"2147483647 + 0 1 2", 2},
+		{"65536 * 65536 1", 1},
+		{"-2147483648 - 1", 1},
+		{"+/ 1e9 1e9 1e9", 1},
+		{"2147483647 + 2147483647 + 1", 1},
+		{"2147483646 + 1", 0},
+	}
+	for _, test := range tests {
+		Reset()
+		Eval(")warnoverflow on")
+		_, err := Eval(test.input)
+		n := 0
+		if err != nil {
+			n = strings.Count(err.Error(), "overflow: ")
+		}
+		if n != test.warnings {
+			t.Errorf("%q: expected %d warnings; got %d (%v)", test.input, test.warnings, n, err)
+		}
+	}
+	// The warning does not change the result, and is off by default.
+	Reset()
+	out, err := Eval("2147483647 + 1")
+	if err != nil || out != "2147483648\n" {
+		t.Errorf("2147483647 + 1 = %q (%v)", out, err)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "x.csv")
+	var tests = []string{
+		"2 3 rho 1 -2 (1/3) 4 5e20 6",
+		"iota 5",
+		"2 2 rho (enclose 'a,b'), 1, (enclose 'line\\nbreak'), 2",
+	}
+	for _, test := range tests {
+		Reset()
+		want, _ := Eval(test)
+		if _, err := Eval(fmt.Sprintf("(%s) csv %q", test, file)); err != nil {
+			t.Fatalf("writing %s: %v", test, err)
+		}
+		got, err := Eval(fmt.Sprintf("csv %q", file))
+		if err != nil {
+			t.Fatalf("reading %s: %v", test, err)
+		}
+		if got != want {
+			t.Errorf("%s: round trip gave\n%s\nwant\n%s", test, got, want)
+		}
+	}
+}
+
+func TestReadv(t *testing.T) {
+	var tests = []struct {
+		input  string
+		expr   string
+		output string
+	}{
+		{"1 2 3\n4.5\t-6\n\n1e3 1/2\n", "readv", "1 2 3 9/2 -6 1000 1/2\n"},
+		{"1 2 3\n4 5 6\n", "rho readv", "6\n"},
+		{"", "rho readv", "0\n"},
+		{"10 20 30", "mean readv", "20\n"},
+		{"apple 2\nfig", "rho readv", "3 5\n"},
+		{"apple 2\nfig", "readv", "apple\n2    \nfig  \n"},
+		{"1 2", "readv = 3; readv", "3\n"},
+		{"2021/01/02", "readv", "2021/01/02\n"},
+	}
+	for _, test := range tests {
+		Reset()
+		conf.SetInput(strings.NewReader(test.input))
+		out, err := Eval(test.expr)
+		if err != nil {
+			t.Errorf("%q with input %q: %v", test.expr, test.input, err)
+			continue
+		}
+		if out != test.output {
+			t.Errorf("%q with input %q: expected %q; got %q", test.expr, test.input, test.output, out)
+		}
+	}
+	// The input is consumed.
+	Reset()
+	conf.SetInput(strings.NewReader("1 2 3"))
+	Eval("x = readv")
+	out, _ := Eval("(rho x), rho readv")
+	if out != "3 0\n" {
+		t.Errorf("reading twice: got %q", out)
+	}
+	// Grouping separators are for literals, not data.
+	Reset()
+	conf.SetGrouping(",", 3)
+	conf.SetInput(strings.NewReader("1,234 5"))
+	out, _ = Eval("readv")
+	if out != "1,234\n5    \n" {
+		t.Errorf("grouped number: got %q", out)
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	Reset()
+	defer Reset()
+	var out bytes.Buffer
+	conf.SetOutput(&out)
+	conf.SetPrompt("[%n %b%%] ")
+	scanner := scan.New(context, "", strings.NewReader("1+1\n)ibase 16\n10 +\n1\n"))
+	parser := parse.NewParser("", scanner, context)
+	for !run.Run(parser, context, true) {
+	}
+	const want = "[1 10%] 2\n\n[2 10%] \n[3 16%]     17\n\n[5 16%] "
+	if got := out.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestPrompt2(t *testing.T) {
+	Reset()
+	defer Reset()
+	if got := conf.Prompt2(); got != config.DefaultPrompt2 {
+		t.Errorf("default: got %q; want %q", got, config.DefaultPrompt2)
+	}
+	conf.SetPrompt2("... ")
+	if got := conf.Prompt2(); got != "... " {
+		t.Errorf("after set: got %q", got)
+	}
+	var out bytes.Buffer
+	conf.SetOutput(&out)
+	conf.SetPrompt("> ")
+	scanner := scan.New(context, "", strings.NewReader("1 +\n2\n"))
+	parser := parse.NewParser("", scanner, context)
+	for !run.Run(parser, context, true) {
+	}
+	const want = "> ... 3\n\n> "
+	if got := out.String(); got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	os.Setenv("IVY_TEST_HARNESS", "from the harness")
+	defer os.Unsetenv("IVY_TEST_HARNESS")
+	Reset()
+	out, err := Eval("getenv 'IVY_TEST_HARNESS'")
+	if err != nil || out != "from the harness\n" {
+		t.Errorf("getenv: got %q (%v)", out, err)
+	}
+	Eval("'IVY_TEST_HARNESS' setenv 'from ivy'")
+	if got := os.Getenv("IVY_TEST_HARNESS"); got != "from ivy" {
+		t.Errorf("setenv: environment has %q", got)
+	}
+}
+
+func TestShell(t *testing.T) {
+	Reset()
+	defer Reset()
+	Eval(")shell on")
+	// The final newline of the command's output is dropped, so a
+	// one-line result prints as exactly one line.
+	out, err := Eval("sh 'echo hello'")
+	if err != nil || out != "hello\n" {
+		t.Errorf("sh: got %q (%v)", out, err)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	// An interrupt while idle is ignored.
+	Reset()
+	if conf.Interrupt() {
+		t.Fatal("interrupt while idle reported an evaluation running")
+	}
+	if out, err := Eval("1+1"); err != nil || out != "2\n" {
+		t.Fatalf("after idle interrupt, 1+1 is %q (%v)", out, err)
+	}
+	// The second expression applies only a few operators; the work
+	// is in the loops of factor.
+	for _, expr := range []string{
+		"- sum 1 2000000000",
+		"factor (nextprime 2**50)*nextprime 2**51",
+	} {
+		Reset()
+		Eval("x = 5")
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			conf.Interrupt()
+		}()
+		_, err := Eval(expr)
+		if err == nil || !strings.Contains(err.Error(), "interrupted") {
+			t.Fatalf("%s: expected interrupted; got %v", expr, err)
+		}
+		out, err := Eval("x")
+		if err != nil || out != "5\n" {
+			t.Fatalf("%s: after interrupt, x is %q (%v)", expr, out, err)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	Reset()
+	defer Reset()
+	Eval(")timeout 0.1")
+	_, err := Eval("factor (nextprime 2**50)*nextprime 2**51")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timed out; got %v", err)
+	}
+	// A timer that fires as the evaluation finishes must not
+	// affect the next one.
+	Eval(")timeout 0.001")
+	for i := 0; i < 100; i++ {
+		Eval("+/iota 2000")
+	}
+	Eval(")timeout 10")
+	if out, err := Eval("1+1"); err != nil || out != "2\n" {
+		t.Fatalf("after timeout, 1+1 is %q (%v)", out, err)
+	}
+}
+
 const demoText = `# This is a demo.
 23
 iota 10
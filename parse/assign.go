@@ -8,6 +8,7 @@ package parse
 // validity checks.
 
 import (
+	"robpike.io/ivy/exec"
 	"robpike.io/ivy/value"
 )
 
@@ -31,6 +32,9 @@ func assignment(context value.Context, b *binary) value.Value {
 		return Assignment{Value: rhs}
 	case *binary:
 		if lhs.op == "[]" {
+			// The variable's data may be shared with other variables
+			// and with the cached results of memoized operators.
+			context.(*exec.Context).ForgetMemos()
 			return indexedAssignment(context, lhs, b.right, rhs)
 		}
 	}
@@ -145,6 +149,12 @@ func lvalueOf(context value.Context, item value.Expr) value.Value {
 		if lvalue.Rank() == 0 {
 			break
 		}
+		if r, ok := lvalue.(value.Range); ok {
+			// A range has no elements to assign to, so give
+			// the variable a vector holding them.
+			lvalue = value.Materialize(context.Config(), r)
+			context.Assign(lhs.name, lvalue)
+		}
 		return lvalue
 	case *binary:
 		if lhs.op == "[]" {
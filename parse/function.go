@@ -14,9 +14,9 @@ import (
 
 // function definition
 //
-//	"op" name arg <eol>
-//	"op" name arg '=' statements <eol>
-//	"op" arg name arg '=' statements <eol>
+//	"op" ["memo"] name arg <eol>
+//	"op" ["memo"] name arg '=' statements <eol>
+//	"op" ["memo"] arg name arg '=' statements <eol>
 //
 // statements:
 //	expressionList
@@ -25,6 +25,11 @@ import (
 func (p *Parser) functionDefn() {
 	p.need(scan.Op)
 	fn := new(exec.Function)
+	// A leading "memo" asks for the results to be cached.
+	if tok := p.peek(); tok.Type == scan.Identifier && tok.Text == "memo" {
+		p.next()
+		fn.Memo = true
+	}
 	// Two identifiers means: op arg.
 	// Three identifiers means: arg op arg.
 	idents := make([]string, 2, 3)
@@ -139,6 +144,14 @@ func references(c *exec.Context, body []value.Expr) []exec.OpDef {
 func doReferences(c *exec.Context, refs *[]exec.OpDef, expr value.Expr) {
 	switch e := expr.(type) {
 	case *unary:
+		if c.UnaryFn[e.op] != nil {
+			addReference(refs, e.op, false)
+		}
+		if e.axis != nil {
+			doReferences(c, refs, e.axis)
+		}
+		doReferences(c, refs, e.right)
+	case *rangeReduce:
 		if c.UnaryFn[e.op] != nil {
 			addReference(refs, e.op, false)
 		}
@@ -147,6 +160,9 @@ func doReferences(c *exec.Context, refs *[]exec.OpDef, expr value.Expr) {
 		if c.BinaryFn[e.op] != nil {
 			addReference(refs, e.op, true)
 		}
+		if e.axis != nil {
+			doReferences(c, refs, e.axis)
+		}
 		doReferences(c, refs, e.left)
 		doReferences(c, refs, e.right)
 	case variableExpr:
@@ -159,6 +175,7 @@ func doReferences(c *exec.Context, refs *[]exec.OpDef, expr value.Expr) {
 	case value.BigInt:
 	case value.BigFloat:
 	case value.BigRat:
+	case value.Complex:
 	case value.Vector:
 	case *value.Matrix:
 	default:
@@ -9,7 +9,15 @@ var helpLines = []string{
 	"Unlike APL, the input is ASCII and the results are exact (but see the next paragraph).",
 	"It uses exact rational arithmetic so it can handle arbitrary precision. Values to be",
 	"input may be integers (3, -1), rationals (1/3, -45/67) or floating point values (1e3,",
-	"-1.5 (representing 1000 and -3/2)).",
+	"-1.5 (representing 1000 and -3/2)). Underscores may separate digits",
+	"for readability, as in 1_000_000 or 0xFF_FF. Complex numbers are written with a j",
+	"separating the real and imaginary parts: 3j4 is 3+4i, and 1j-1/2 is 1-i/2.",
+	"A complex number with a zero imaginary part is real, so 5j0 is just 5. Complex",
+	"values print in the form they are written, in the output base, so they can be read",
+	"back. In bases of 20 and above, j is a numeral and complex numbers cannot be written.",
+	"The parts may be of any real type. Arithmetic, comparison for equality, abs,",
+	"real, imag, + (conjugate), and ** (exponential, and powers with an integer",
+	"exponent) support complex values.",
 	"",
 	"Some functions such as sqrt are irrational. When ivy evaluates an irrational",
 	"function, the result is stored in a high-precision floating-point number (default",
@@ -34,9 +42,19 @@ var helpLines = []string{
 	"Only a subset of APL's functionality is implemented, but the intention is to",
 	"have most numerical operations supported eventually.",
 	"",
+	"Comments run from # to the end of the line. A block comment begins with /* and",
+	"ends with an asterisk followed by a slash; it may span several lines and may be",
+	"nested.",
+	"",
 	"Semicolons separate multiple statements on a line. Variables are alphanumeric and are",
 	"assigned with the = operator. Assignment is an expression.",
 	"",
+	"A line that ends with an operator or an opening parenthesis or bracket is",
+	"incomplete and continues on the next line, so",
+	"\tx = 1 2 3 +",
+	"\t    4 5 6",
+	"sets x to 5 7 9. A blank line abandons an incomplete line.",
+	"",
 	"After each successful expression evaluation, the result is stored in the variable",
 	"called _ (underscore) so it can be used in the next expression.",
 	"",
@@ -47,34 +65,89 @@ var helpLines = []string{
 	"",
 	"\tName              APL   Ivy     Meaning",
 	"\tRoll              ?B    ?       One integer selected randomly from the first B integers",
+	"\tRandom                  rand    Uniform random floats in [0, 1); B is a count or a shape",
+	"\tRandom normal           randn   Random floats from the standard normal distribution; ditto",
 	"\tCeiling           ⌈B    ceil    Least integer greater than or equal to B",
 	"\tFloor             ⌊B    floor   Greatest integer less than or equal to B",
+	"\tRound                   round   Nearest integer to B; halves round to even, so round 2.5 is 2",
 	"\tShape             ⍴B    rho     Number of components in each dimension of B",
 	"\tNot               ∼B    not     Logical: not 1 is 0, not 0 is 1",
 	"\tAbsolute value    ∣B    abs     Magnitude of B",
+	"\tReal part               real    Real part of B",
+	"\tImaginary part          imag    Imaginary part of B; 0 if B is real",
 	"\tIndex generator   ⍳B    iota    Vector of the first B integers",
+	"\tWhere             ⍸B    where   Indices of the 1s in B; a count n repeats its index n times;",
+	"\t                                for a matrix, a matrix of their coordinates",
 	"\tExponential       ⋆B    **      e to the B power",
 	"\tNegation          −B    -       Changes sign of B",
-	"\tIdentity          +B    +       No change to B",
+	"\tConjugate         +B    +       Complex conjugate of B; no change to real B",
 	"\tSignum            ×B    sgn     ¯1 if B<0; 0 if B=0; 1 if B>0",
 	"\tReciprocal        ÷B    /       1 divided by B",
 	"\tRavel             ,B    ,       Reshapes B into a vector",
-	"\tMatrix inverse    ⌹B            Inverse of matrix B",
+	"\tUnique            ∪B    unique  Distinct elements (rows of a matrix) of B, in order of appearance",
+	"\tTally                   tally   2-row matrix of the distinct elements of B and their counts",
+	"\tEnclose           ⊂B    enclose Encloses array B in a scalar box",
+	"\tDisclose          ⊃B    disclose Contents of box B; a vector of boxes becomes a matrix",
+	"\tDepth             ≡B    depth   Levels of nesting in B; 0 for a simple scalar",
+	"\tFirst             ↑B    first   First item (element or row) of B; zeros if B is empty",
+	"\tLast                    last    Last item of B",
+	"\tHead                    head    B without its last item; ¯1↓B",
+	"\tTail                    tail    B without its first item; 1↓B",
+	"\tMatrix inverse    ⌹B    inv     Inverse of matrix B",
 	"\tPi times          ○B            Multiply by π",
 	"\tLogarithm         ⍟B    log     Natural logarithm of B",
 	"\tReversal          ⌽B    rot     Reverse elements of B along last axis",
 	"\tReversal          ⊖B    flip    Reverse elements of B along first axis",
 	"\tGrade up          ⍋B    up      Indices of B which will arrange B in ascending order",
 	"\tGrade down        ⍒B    down    Indices of B which will arrange B in descending order",
+	"\t                                The sort is stable; a matrix is sorted by rows",
+	"\tSort                    sort    B sorted into ascending order; B[up B]",
+	"\tSort down               sortdown B sorted into descending order; B[down B]",
 	"\tExecute           ⍎B    ivy     Execute an APL (ivy) expression",
 	"\tMonadic format    ⍕B    text    A character representation of B",
+	"\tUpper case              upper   Chars of B in upper case",
+	"\tLower case              lower   Chars of B in lower case",
+	"\tTrim                    trim    B without leading and trailing white space; in a char matrix, per row",
+	"\tShell command           sh      Standard output of the shell command B; requires ) shell on",
+	"\tEnvironment             getenv  Value of the environment variable named B; empty if unset",
+	"\tRead CSV                csv     Matrix of the fields of the CSV file named B; non-numbers are enclosed",
+	"\tJSON                    json    JSON text of B; matrices become nested arrays, boxes their contents",
+	"\tFrom JSON               unjson  Value of the JSON text B; arrays of equal arrays become matrices",
+	"\t                                Strings are not arrays, so a char matrix returns as enclosed strings",
+	"\tBase64                  base64  Base64 encoding of the string or bytes (integers 0 to 255) B",
+	"\tFrom base64             unbase64 String or, if not UTF-8, bytes encoded in base64 by B",
+	"\tHex                     hex     Hexadecimal encoding of the string or bytes (integers 0 to 255) B",
+	"\tFrom hex                unhex   String or, if not UTF-8, bytes encoded in hexadecimal by B",
+	"\tDate                    date    Time written as the string B, or B seconds after 1970-01-01 UTC",
+	"\tSince                   since   Seconds from time B to now",
+	"\tHuman time              humantime B seconds as days, hours, minutes and seconds, as in 1d 1h 1m 1s",
 	"\tMonadic transpose ⍉B    transp  Reverse the axes of B",
+	"\tDeterminant             det     Determinant of square matrix B",
 	"\tFactorial         !B    !       Product of integers 1 to B",
+	"\tGamma                   gamma   Gamma function; gamma B is !B-1 for integer B",
+	"\tLog gamma               lgamma  Natural logarithm of abs gamma B",
+	"\tPrimality               isprime 1 if B is prime, 0 if not; probabilistic beyond 2**64",
+	"\tNext prime              nextprime Smallest prime greater than B",
+	"\tFactorization           factor  Prime factors of B in increasing order, with multiplicity",
+	"\tMean                    mean    Arithmetic mean of B; exact for integers and rationals",
+	"\tVariance                var     Sample variance of B, dividing by one less than its length",
+	"\tStandard deviation      stddev  Square root of var B",
+	"\tMedian                  median  Middle value of sorted B; mean of the middle two if even",
+	"\t                                For a matrix, these apply along the last axis",
+	"\tContinued fraction      cfrac   Continued-fraction terms of B; for a float, enough to equal B",
+	"\tFrom continued fraction fromcfrac Number whose continued fraction has the terms B",
+	"\tRoots                   roots   Roots of the polynomial with coefficients B, highest power first",
 	"\tBitwise not             ^       Bitwise complement of B (integer only)",
 	"\tSquare root       B⋆.5  sqrt    Square root of B.",
 	"\tSine                    sin     sin(A); APL uses binary ○ (see below)",
 	"\tCosine                  cos     cos(A); ditto",
 	"\tTangent                 tan     tan(A); ditto",
+	"\tHyperbolic sine         sinh    sinh(B)",
+	"\tHyperbolic cosine       cosh    cosh(B)",
+	"\tHyperbolic tangent      tanh    tanh(B)",
+	"\tInverse sinh            asinh   asinh(B)",
+	"\tInverse cosh            acosh   acosh(B); B must be at least 1",
+	"\tInverse tanh            atanh   atanh(B); abs B must be less than 1",
 	"",
 	"Binary operators",
 	"",
@@ -86,6 +159,7 @@ var helpLines = []string{
 	"\t                            div     A divided by B (Euclidean)",
 	"\t                            idiv    A divided by B (Go)",
 	"\tExponentiation        A⋆B   **      A raised to the B power",
+	"\tRoot                        root    The A'th root of B, A a positive integer; exact for perfect powers",
 	"\tCircle                A○B           Trigonometric functions of B selected by A",
 	"\t                                    A=1: sin(B) A=2: cos(B) A=3: tan(B); ¯A for inverse",
 	"\t                            sin     sin(B); ivy uses traditional name.",
@@ -96,34 +170,76 @@ var helpLines = []string{
 	"\t                            atan    arctan(B); ivy uses traditional name.",
 	"\tDeal                  A?B   ?       A distinct integers selected randomly from the first B integers",
 	"\tMembership            A∈B   in      1 for elements of A present in B; 0 where not.",
+	"\tCount                       count   Number of times each element of A occurs in B",
+	"\tUnion                 A∪B   union   Distinct elements of A and B, in order of appearance",
+	"\tIntersection          A∩B   intersect Distinct elements of A that are also in B",
+	"\tWithout               A~B   diff    Distinct elements of A that are not in B",
 	"\tMaximum               A⌈B   max     The greater value of A or B",
 	"\tMinimum               A⌊B   min     The smaller value of A or B",
+	"\tRound to multiple           round   Multiple of A nearest B, halves to even: 0.01 round 3.14159 is 3.14",
+	"\tCeiling to multiple         ceil    Least multiple of A greater than or equal to B",
+	"\tFloor to multiple           floor   Greatest multiple of A less than or equal to B",
+	"\tApproximation               approx  Nearest fraction to B with denominator at most A",
 	"\tReshape               A⍴B   rho     Array of shape A with data B",
-	"\tTake                  A↑B   take    Select the first (or last) A elements of B according to ×A",
-	"\tDrop                  A↓B   drop    Remove the first (or last) A elements of B according to ×A",
+	"\t                                    repeated as needed; if B is empty, the data are zeros",
+	"\tTake                  A↑B   take    Select the first (or last) A elements of B according to ×A;",
+	"\t                                    taking too many pads with zeros (spaces for chars);",
+	"\t                                    if A is a vector, its elements apply to successive axes of B",
+	"\tDrop                  A↓B   drop    Remove the first (or last) A elements of B according to ×A;",
+	"\t                                    if A is a vector, its elements apply to successive axes of B",
 	"\tDecode                A⊥B   decode  Value of a polynomial whose coefficients are B at A",
+	"\tPolynomial value            polyval Value at B of the polynomial with coefficients A,",
+	"\t                                    highest power first",
 	"\tEncode                A⊤B   encode  Base-A representation of the value of B",
 	"\tResidue               A∣B           B modulo A",
 	"\t                            mod     A modulo B (Euclidean)",
 	"\t                            imod    A modulo B (Go)",
+	"\tGCD                   A∨B   gcd     Greatest common divisor of A and B",
+	"\tLCM                   A∧B   lcm     Least common multiple of A and B",
+	"\tModular power               powmod  B raised to the power A[1], modulo A[2]; A[1] may be negative",
 	"\tCatenation            A,B   ,       Elements of B appended to the elements of A",
 	"\tExpansion             A\\B   fill    Insert zeros (or blanks) in B corresponding to zeros in A",
 	"\t                                    In ivy: abs(A) gives count, A <= 0 inserts zero (or blank)",
+	"\t                            \\       Same as fill; both apply to the last axis of a matrix",
 	"\tCompression           A/B   sel     Select elements in B corresponding to ones in A",
-	"\t                                    In ivy: abs(A) gives count, A <= 0 inserts zero",
+	"\t                                    In ivy: abs(A) gives count, A <= 0 inserts zero;",
+	"\t                                    applies to the last axis of a matrix",
+	"\tSelection                   select  Elements of the first of B where A is 1, of the second where 0;",
+	"\t                                    B is a matrix with 2 rows or 2 boxes; a branch may be a scalar",
 	"\tIndex of              A⍳B   iota    The location (index) of B in A; 1+⌈/⍳⍴A if not found",
 	"\t                                    In ivy: origin-1 if not found (i.e. 0 if one-indexed)",
-	"\tMatrix divide         A⌹B           Solution to system of linear equations Ax = B",
+	"\tMatrix divide         A⌹B   inv     Solution to system of linear equations Ax = B;",
+	"\t                                    least-squares solution if A has more rows than columns",
+	"\tDot product                 dot     Same as A+.×B",
+	"\tCross product               cross   Cross product of the 3-vectors A and B",
+	"\tKronecker product           kron    Block array of each element of A times B;",
+	"\t                                    each axis is the product of the axes of A and B",
+	"\tMatrix power                mpow    Square matrix A multiplied by itself B times;",
+	"\t                                    the identity if B is 0, a power of the inverse if B < 0",
+	"\tGrade up              A⍋B   up      Indices that sort the chars of B in the order of alphabet A;",
+	"\t                                    chars not in A sort last",
+	"\tGrade down            A⍒B   down    Indices that sort the chars of B in descending order of A",
 	"\tRotation              A⌽B   rot     The elements of B are rotated A positions left",
 	"\tRotation              A⊖B   flip    The elements of B are rotated A positions along the first axis",
 	"\tLogarithm             A⍟B   log     Logarithm of B to base A",
+	"\t                                    exact if A and B are exact and B is an integral power of A",
 	"\tDyadic format         A⍕B   text    Format B into a character matrix according to A",
 	"\t                                    A is the textual format (see format special command);",
 	"\t                                    otherwise result depends on length of A:",
 	"\t                                    1 gives decimal count, 2 gives width and decimal count,",
 	"\t                                    3 gives width, decimal count, and style ('d', 'e', 'f', etc.).",
-	"\tGeneral transpose     A⍉B           The axes of B are ordered by A",
-	"\tCombinations          A!B   !       Number of combinations of B taken A at a time",
+	"\t                                    If B is a time, A is a Go time layout such as '2006-01-02'.",
+	"\tSet environment             setenv  Set the environment variable named A to the string B; returns B",
+	"\tMatch                       match   1 if the regular expression A matches the string B, 0 if not;",
+	"\t                                    a char matrix or vector of enclosed strings B gives a vector",
+	"\tFind                        find    Enclosed substrings of B matched by the regular expression A;",
+	"\t                                    with capture groups, a matrix of the groups of each match",
+	"\tWrite CSV                   csv     Write the rows of matrix A to the CSV file named B;",
+	"\t                                    returns the number of rows written",
+	"\tGeneral transpose     A⍉B   transp  The axes of B are ordered by A: axis i of B becomes axis A[i];",
+	"\t                                    repeated axes take the diagonal, as in 1 1 transp B",
+	"\tCombinations          A!B   !       Number of combinations of B taken A at a time;",
+	"\t                                    uses gamma function for non-integer A or B",
 	"\tLess than             A<B   <       Comparison: 1 if true, 0 if false",
 	"\tLess than or equal    A≤B   <=      Comparison: 1 if true, 0 if false",
 	"\tEqual                 A=B   ==      Comparison: 1 if true, 0 if false",
@@ -139,18 +255,33 @@ var helpLines = []string{
 	"\tBitwise or                  |       Bitwise A or B (integer only)",
 	"\tBitwise xor                 ^       Bitwise A exclusive or B (integer only)",
 	"\tLeft shift                  <<      A shifted left B bits (integer only)",
+	"\t                            shiftl  Same as <<",
 	"\tRight Shift                 >>      A shifted right B bits (integer only)",
+	"\t                            shiftr  Same as >>",
 	"",
 	"Operators and axis indicator",
 	"",
 	"\tName                APL  Ivy  APL Example  Ivy Example  Meaning (of example)",
 	"\tReduce (last axis)  /    /    +/B          +/B          Sum across B",
-	"\tReduce (first axis) ⌿         +⌿B                       Sum down B",
+	"\t                              2+/B         2 +/B        N-wise: sums of adjacent pairs in B;",
+	"\t                                                    with a negative count, each window is taken backward",
+	"\tReduce (first axis) ⌿    /%   +⌿B          +/%B         Sum down B",
 	"\tScan (last axis)    \\    \\    +\\B          +\\B          Running sum across B",
-	"\tScan (first axis)   ⍀         +⍀B                       Running sum down B",
+	"\tScan (first axis)   ⍀    \\%   +⍀B          +\\%B         Running sum down B",
 	"\tInner product       .    .    A+.×B        A +.* B      Matrix product of A and B",
 	"\tOuter product       ∘.   o.   A∘.×B        A o.* B      Outer product of A and B",
 	"\t                                                    (lower case o; may need preceding space)",
+	"\t                                                    (either operator may be user-defined, as in A min.+ B)",
+	"\tRange sum                sum  +/f⍳N        f sum 1 N    Sum of f applied to each integer from 1 to N",
+	"\tRange product            prod ×/f⍳N        f prod 1 N   Product of the same",
+	"\t                                                    (f is a unary operator; the range is never materialized)",
+	"\tAxis                [K]  [    ⌽[1]B        rot[1] B     Reverse B along axis 1",
+	"\t                              1⌽[1]B       1 rot[1] B   Rotate B along axis 1",
+	"\t                              1 0/[1]B     1 0 sel[1] B Select row 1 of B",
+	"\t                              1 0 1\\[1]B   1 0 1 \\[1] B Insert an empty row in B",
+	"\t                              A,[1]B       A ,[1] B     Join A and B along axis 1",
+	"\t                              A,[0.5]B     A ,[0.5] B   Laminate A and B along a new first axis",
+	"\t                                                    (rot, flip, sel, fill, \\ and , only; the axis counts from the origin)",
 	"",
 	"Type-converting operations",
 	"",
@@ -165,6 +296,34 @@ var helpLines = []string{
 	"precision, about 3000 decimal digits truncated according to the floating point",
 	"precision setting.",
 	"",
+	"Reading data",
+	"",
+	"The name readv, unless it is a variable, reads the rest of the standard input",
+	"and returns the whitespace-separated words in it: a vector if they are all",
+	"numbers, otherwise a char matrix with one word per row. It suits pipelines:",
+	"",
+	"\tcat data | ivy -e 'mean readv'",
+	"",
+	"Times",
+	"",
+	"A time is a scalar holding an instant. The name now, unless it is a variable,",
+	"is the current time, and date makes a time from a string such as '2024-01-15'",
+	"or '2024-01-15 10:30', or from a number of seconds since 1970-01-01 UTC.",
+	"Adding or subtracting a number of seconds to a time yields a time, subtracting",
+	"two times yields the seconds between them, and times compare in order:",
+	"",
+	"\t((date '2024-03-01') - date '2024-01-15') / 86400",
+	"\t46",
+	"",
+	"The unary since gives the seconds elapsed since a time, and humantime writes a",
+	"number of seconds readably:",
+	"",
+	"\thumantime 90061",
+	"\t1d 1h 1m 1s",
+	"",
+	"Times print according to ) dateformat; '2006-01-02' text t formats the time t",
+	"with any Go time layout.",
+	"",
 	"Character data",
 	"",
 	"Strings are vectors of \"chars\", which are Unicode code points (not bytes).",
@@ -180,13 +339,32 @@ var helpLines = []string{
 	"contain only valid Unicode values. Thus in ivy \"\\x80\" is illegal, although it is",
 	"a legal one-byte string in Go.",
 	"",
+	"Interpreted strings accept Go's escape sequences, such as \\n, \\t, \\\\, \\\",",
+	"\\xHH, \\uHHHH and \\UHHHHHHHH; an invalid one is an error.",
+	"",
 	"Strings can be printed. If a vector contains only chars, it is printed without",
-	"spaces between them.",
+	"spaces between them. Printing shows the characters themselves, so \"a\\tb\" prints",
+	"with a tab, but operator listings and ) save re-escape control characters.",
 	"",
 	"Chars have restricted operations. Printing, comparison, indexing and so on are",
 	"legal but arithmetic is not, and chars cannot be converted automatically into other",
 	"singleton values (ints, floats, and so on). The unary operators char and code",
-	"enable transcoding between integer and char values.",
+	"enable transcoding between integer and char values. They apply to each element",
+	"of an array, so code 'ab' is 97 98 and char 97 98 is 'ab'; char rejects",
+	"non-integers, surrogates and values beyond the Unicode range.",
+	"",
+	"Nested arrays",
+	"",
+	"The unary operator enclose wraps an array in a box, which is a scalar and so",
+	"can be an element of another array. Thus",
+	"\t(enclose 1 2 3), (enclose 4 5), 6",
+	"is a three-element vector whose first two elements are boxes. Boxes print with",
+	"a frame drawn around their contents. Enclosing a simple scalar has no effect.",
+	"The operator disclose undoes enclose; applied to a vector of boxes, it returns",
+	"the matrix whose rows are the contents, padded with zeros or spaces as by take",
+	"to the length of the longest. The operator depth reports the level of nesting:",
+	"0 for a simple scalar, 1 for an array of simple scalars, and one more for each",
+	"level of enclosure.",
 	"",
 	"User-defined operators",
 	"",
@@ -240,10 +418,28 @@ var helpLines = []string{
 	"\tbar 3",
 	"\tresult: 1/3",
 	"",
+	"To have an operator remember its results, write \"memo\" after the 'op'.",
+	"A later call with the same arguments returns the remembered result without",
+	"evaluating the body again. Each operator remembers up to 10000 results.",
+	"They are forgotten when a global variable they used is assigned, when an",
+	"operator is defined, and after a special command such as )origin, which may",
+	"change the configuration. A result that uses a local variable of a calling",
+	"operator is not remembered.",
+	"\top memo fib n = ((2 2 rho 1 1 1 0) mpow n)[1][2]",
+	"\tfib 100",
+	"\tresult: 354224848179261915075",
+	"",
 	"Within a user-defined operator, identifiers are local to the invocation unless",
 	"they are undefined in the operator but defined globally, in which case they refer to",
 	"the global variable. A mechanism to declare locals may come later.",
 	"",
+	"An error during evaluation names the operator that failed and the types and",
+	"shapes of its operands, and, when reading a file, the line on which the",
+	"operator appears, which for a user-defined operator is in its definition:",
+	"",
+	"\t1 2 + 3 4 5",
+	"\t+: length mismatch: 2 3 (int vector 2 + int vector 3)",
+	"",
 	"Special commands",
 	"",
 	"Ivy accepts a number of special commands, introduced by a right paren",
@@ -251,61 +447,191 @@ var helpLines = []string{
 	"is not specified. For these commands, numbers are always read and printed",
 	"base 10 and must be non-negative on input.",
 	"",
+	"When ivy is run interactively, an interrupt (typically control-C) stops the",
+	"current evaluation with the error \"interrupted\" and returns to the prompt,",
+	"leaving variables and operators as they were.",
+	"",
 	"\t) help",
 	"\t\tDescribe the special commands. Run )help <topic> to learn more",
 	"\t\tabout a topic, )help <op> to learn more about an operator.",
 	"\t) base 0",
 	"\t\tSet the number base for input and output. The commands ibase and",
 	"\t\tobase control setting of the base for input and output alone,",
-	"\t\trespectively.  Base 0 allows C-style input: decimal, with 037 being",
-	"\t\toctal and 0x10 being hexadecimal. If the base is greater than 10,",
+	"\t\trespectively.  Base 0 allows C-style input: decimal, with 037 or",
+	"\t\t0o37 being octal, 0x10 being hexadecimal and 0b101 being binary.",
+	"\t\tIn base 0, such numbers may have a fraction and a binary exponent,",
+	"\t\tas in 0x1.8p3. If the base is greater than 10,",
 	"\t\tany identifier formed from valid numerals in the base system, such",
 	"\t\tas abe for base 16, is taken to be a number. TODO: To output",
 	"\t\tlarge integers and rationals, base must be one of 0 2 8 10 16.",
-	"\t\tFloats are always printed base 10.",
+	"\t\tIf the output base is 2, 8 or 16, floats are printed exactly in",
+	"\t\tthat base with a binary exponent, like C's %a: 1.5 prints as",
+	"\t\t0x1.8p0 in base 16. Otherwise floats are printed base 10.",
+	"\t\tThe argument \"default\" restores the default base, 0.",
+	"\t) cd \"dir\"",
+	"\t\tChange the working directory, used to resolve relative file names",
+	"\t\tin ) get and ) save. If no directory is specified, change to the",
+	"\t\tuser's home directory.",
+	"\t) clear",
+	"\t\tDelete all variables and user-defined operators, and restore the",
+	"\t\tbase, formats and origin to their defaults. The prompt and other",
+	"\t\tsettings are unchanged. With an argument of \"vars\" or \"ops\",",
+	"\t\tdelete only the variables or only the operators.",
+	"\t) color on|off",
+	"\t\tPrint values and error messages in color, using ANSI escape",
+	"\t\tsequences, when the output is a terminal. The default is off.",
+	"\t\tWith no argument, print the setting.",
 	"\t) cpu",
 	"\t\tPrint the duration of the last interactive calculation.",
 	"\t) debug name 0|1",
 	"\t\tToggle or set the named debugging flag. With no argument, lists",
-	"\t\tthe settings.",
-	"\t) demo",
+	"\t\tthe settings. The parse flag prints the parse tree of each",
+	"\t\texpression before it is evaluated, fully parenthesized so the",
+	"\t\tgrouping is explicit. The time flag prints, for each operator applied",
+	"\t\toutside user-defined operators, the time it took and the type",
+	"\t\tand shape of its result.",
+	"\t) dateformat \"\"",
+	"\t\tSet the Go time layout, such as \"2006-01-02 15:04\", used to print",
+	"\t\ttimes. The default, \"\", prints times in RFC 3339 format. With no",
+	"\t\targument, print the layout.",
+	"\t) demo name",
 	"\t\tRun a line-by-line interactive demo. Requires a Go installation.",
+	"\t\tIf a name is given, run the script demo/name.ivy, or the program",
+	"\t\tdemo/name.go if there is one, instead of the standard demo.",
 	"\t) format \"\"",
 	"\t\tSet the format for printing values. If empty, the output is printed",
 	"\t\tusing the output base. If non-empty, the format determines the",
 	"\t\tbase used in printing. The format is in the style of golang.org/pkg/fmt.",
 	"\t\tFor floating-point formats, flags and width are ignored.",
+	"\t\tThe argument \"default\" restores the default, empty format.",
+	"\t) format int|rat|float \"\"",
+	"\t\tSet the format for printing values of just one type: integers,",
+	"\t\trationals or floating-point numbers. A type's own format takes",
+	"\t\tprecedence over the general one, which it otherwise inherits.",
+	"\t\tWith no format, print the format for the type; \"default\" removes it.",
+	"\t\tWith no arguments, ) format also lists the formats for types.",
+	"\t) format sci|fixed|auto",
+	"\t\tSet the notation for floating-point values printed with the default",
+	"\t\tformat or a %g format: always scientific (1.5e+02), always fixed",
+	"\t\t(150), or chosen automatically as %g does, the default. The form",
+	"\t\t) format auto low high uses fixed notation when the decimal exponent",
+	"\t\tis at least low and less than high. Huge values always use scientific",
+	"\t\tnotation.",
+	"\t) format rational on|off",
+	"\t\tIf off, print rationals as floating-point numbers, rounded to the",
+	"\t\tfloating-point precision, rather than exactly. Their values are",
+	"\t\tunchanged and computation with them remains exact. The default is",
+	"\t\ton. With no argument, print the setting.",
 	"\t) get \"save.ivy\"",
 	"\t\tRead input from the named file; return to interactive execution",
 	"\t\tafterwards. If no file is specified, read from \"save.ivy\".",
+	"\t\tIf the name begins with http:// or https://, the file is",
+	"\t\tfetched from that URL.",
 	"\t\t(Unimplemented on mobile.)",
+	"\t) grouping \",\" 3",
+	"\t\tPrint decimal integers with the separator between each group of",
+	"\t\tdigits, as in 1,000,000; the group size defaults to 3. Numbers",
+	"\t\twritten with the same grouping are accepted on input. Use",
+	"\t\t)grouping default to turn grouping off.",
+	"\t) history n",
+	"\t\tIf n is absent, list the most recent input lines, numbered.",
+	"\t\tOtherwise, run input line n again. The command ) history size 100",
+	"\t\tsets how many lines are remembered; 100 is the default.",
+	"\t) intbits 0",
+	"\t\tMake integer arithmetic wrap around, as in machine arithmetic:",
+	"\t\tinteger results of operators such as +, *, ** and << are reduced",
+	"\t\tto two's-complement integers of this many bits, so with )intbits",
+	"\t\t32, 0x7fffffff + 1 is -2147483648. If intbits is 0, the default,",
+	"\t\tintegers have arbitrary precision. Otherwise it must be between",
+	"\t\t2 and 64.",
 	"\t) maxbits 1e6",
 	"\t\tTo avoid consuming too much memory, if an integer result would",
 	"\t\trequire more than this many bits to store, abort the calculation.",
 	"\t\tIf maxbits is 0, there is no limit; the default is 1e6.",
+	"\t) maxdepth 1e4",
+	"\t\tTo stop runaway recursion, if calls of user-defined operators",
+	"\t\tnest more than this deep, abort the calculation. If maxdepth",
+	"\t\tis 0, there is no limit; the default is 1e4.",
 	"\t) maxdigits 1e4",
 	"\t\tTo avoid overwhelming amounts of output, if an integer has more",
 	"\t\tthan this many digits, print it using the defined floating-point",
 	"\t\tformat. If maxdigits is 0, integers are always printed as integers.",
+	"\t) nan on|off",
+	"\t\tIf on, arithmetic that has no finite result yields the special",
+	"\t\tfloating-point values Inf, -Inf and NaN, as in IEEE arithmetic,",
+	"\t\trather than an error: 1/0 is Inf, 0/0 and log -1 are NaN. NaN",
+	"\t\tpropagates through elementwise operations and is unequal to",
+	"\t\teverything, itself included. The default is off.",
+	"\t\tWith no argument, print the setting.",
 	"\t) op X",
 	"\t\tIf X is absent, list all user-defined operators. Otherwise,",
 	"\t\tshow the definition of the user-defined operator X. Inside the",
 	"\t\tdefinition, numbers are always shown base 10, ignoring the ibase",
 	"\t\tand obase.",
 	"\t) origin 1",
-	"\t\tSet the origin for indexing a vector or matrix.",
+	"\t\tSet the origin for indexing a vector or matrix. The argument",
+	"\t\t\"default\" restores the default origin, 1.",
+	"\t) parallel on|off",
+	"\t\tIf on, reductions such as +/ of large vectors of integers or",
+	"\t\trationals by associative operators are split across the available",
+	"\t\tCPUs. The result is the same either way. The default is on.",
+	"\t\tWith no argument, print the setting.",
 	"\t) prec 256",
 	"\t\tSet the precision (mantissa length) for floating-point values.",
 	"\t\tThe value is in bits. The exponent always has 32 bits.",
 	"\t) prompt \"\"",
-	"\t\tSet the interactive prompt.",
+	"\t\tSet the interactive prompt. Each time it is printed, %n in the",
+	"\t\tprompt becomes the number of the next input line, %b the input",
+	"\t\tbase and %% a percent sign, so ) prompt \"%n> \" numbers the lines.",
+	"\t\tWith no argument, print the prompt.",
+	"\t) prompt2 \"    \"",
+	"\t\tSet the prompt for the continuation of an incomplete line. The",
+	"\t\tdefault is four spaces. With no argument, print the prompt.",
+	"\t) pwd",
+	"\t\tPrint the working directory.",
 	"\t) save \"save.ivy\"",
 	"\t\tWrite definitions of user-defined operators and variables to the",
 	"\t\tnamed file, as ivy textual source. If no file is specified, save to",
-	"\t\t\"save.ivy\".",
+	"\t\t\"save.ivy\". If the file name is followed by a list of names, save",
+	"\t\tonly those operators and variables, plus any operators they use.",
+	"\t\tThe configuration is then not saved. If the first argument is -a,",
+	"\t\tappend to the file rather than replacing it.",
 	"\t\t(Unimplemented on mobile.)",
 	"\t) seed 0",
-	"\t\tSet the seed for the ? operator.",
+	"\t\tSet the seed for the unary (roll) and binary (deal) ? operators.",
+	"\t\tSetting the same seed again repeats the same sequence of values.",
+	"\t) shell off",
+	"\t\tAllow the sh operator to run shell commands. Because a script can",
+	"\t\tthen do anything the user can, the default is off.",
+	"\t) time expression",
+	"\t\tEvaluate the expression and print the wall-clock and CPU time",
+	"\t\tit took, followed by the result.",
+	"\t) timeout 0",
+	"\t\tIf an evaluation takes longer than this many seconds, which",
+	"\t\tmay be fractional, abort it. This guards against runaway",
+	"\t\tscripts. If timeout is 0, there is no limit; the default is 0.",
+	"\t) tolerance 0",
+	"\t\tSet the comparison tolerance, as in APL's ⎕CT. Rationals and floats",
+	"\t\tx and y compare equal, for == != < <= > >= and the operators built",
+	"\t\ton them, if |x-y| is at most the tolerance times the larger of |x|",
+	"\t\tand |y|. The default, 0, makes comparisons exact. With no argument,",
+	"\t\tprint the setting.",
+	"\t) undef X",
+	"\t\tDelete the user-defined operator X. If X is defined as both a",
+	"\t\tunary and a binary operator, both are deleted; to delete only",
+	"\t\tone, follow the name with \"unary\" or \"binary\".",
+	"\t) vars X",
+	"\t\tIf X is absent, list all variables, with the type and shape of",
+	"\t\teach. Scalar values are also printed. Otherwise, describe only",
+	"\t\tthe variable X.",
+	"\t) warnoverflow off",
+	"\t\tPrint a warning to the error output whenever the result of an",
+	"\t\tinteger +, - or * does not fit in a small integer and is promoted",
+	"\t\tto a big integer. The result itself is unchanged.",
+	"\t) width 0",
+	"\t\tWrap printed vectors and matrices so no line is longer than this",
+	"\t\tmany characters. Lines break between elements, and continuation",
+	"\t\tlines are indented. If width is 0, output is not wrapped.",
 }
 
 type helpIndexPair struct {
@@ -313,84 +639,167 @@ type helpIndexPair struct {
 }
 
 var helpUnary = map[string]helpIndexPair{
-	"?":      {43, 43},
-	"ceil":   {44, 44},
-	"floor":  {45, 45},
-	"rho":    {46, 46},
-	"not":    {47, 47},
-	"abs":    {48, 48},
-	"iota":   {49, 49},
-	"**":     {50, 50},
-	"-":      {51, 51},
-	"+":      {52, 52},
-	"sgn":    {53, 53},
-	"/":      {54, 54},
-	",":      {55, 55},
-	"log":    {58, 58},
-	"rot":    {59, 59},
-	"flip":   {60, 60},
-	"up":     {61, 61},
-	"down":   {62, 62},
-	"ivy":    {63, 63},
-	"text":   {64, 64},
-	"transp": {65, 65},
-	"!":      {66, 66},
-	"^":      {67, 67},
-	"sqrt":   {68, 68},
-	"sin":    {69, 71},
-	"cos":    {69, 71},
-	"tan":    {69, 71},
-	"code":   {152, 152},
-	"char":   {153, 153},
-	"float":  {154, 154},
+	"?":         {61, 61},
+	"rand":      {62, 62},
+	"randn":     {63, 63},
+	"ceil":      {64, 64},
+	"floor":     {65, 65},
+	"round":     {66, 66},
+	"rho":       {67, 67},
+	"not":       {68, 68},
+	"abs":       {69, 69},
+	"real":      {70, 70},
+	"imag":      {71, 71},
+	"iota":      {72, 72},
+	"where":     {73, 73},
+	"**":        {75, 75},
+	"-":         {76, 76},
+	"+":         {77, 77},
+	"sgn":       {78, 78},
+	"/":         {79, 79},
+	",":         {80, 80},
+	"unique":    {81, 81},
+	"tally":     {82, 82},
+	"enclose":   {83, 83},
+	"disclose":  {84, 84},
+	"depth":     {85, 85},
+	"first":     {86, 86},
+	"last":      {87, 87},
+	"head":      {88, 88},
+	"tail":      {89, 89},
+	"inv":       {90, 90},
+	"log":       {92, 92},
+	"rot":       {93, 93},
+	"flip":      {94, 94},
+	"up":        {95, 95},
+	"down":      {96, 96},
+	"sort":      {98, 98},
+	"sortdown":  {99, 99},
+	"ivy":       {100, 100},
+	"text":      {101, 101},
+	"upper":     {102, 102},
+	"lower":     {103, 103},
+	"trim":      {104, 104},
+	"sh":        {105, 105},
+	"getenv":    {106, 106},
+	"csv":       {107, 107},
+	"json":      {108, 108},
+	"unjson":    {109, 109},
+	"base64":    {111, 111},
+	"unbase64":  {112, 112},
+	"hex":       {113, 113},
+	"unhex":     {114, 114},
+	"date":      {115, 115},
+	"since":     {116, 116},
+	"humantime": {117, 117},
+	"transp":    {118, 118},
+	"det":       {119, 119},
+	"!":         {120, 120},
+	"gamma":     {121, 121},
+	"lgamma":    {122, 122},
+	"isprime":   {123, 123},
+	"nextprime": {124, 124},
+	"factor":    {125, 125},
+	"mean":      {126, 126},
+	"var":       {127, 127},
+	"stddev":    {128, 128},
+	"median":    {129, 129},
+	"cfrac":     {131, 131},
+	"fromcfrac": {132, 132},
+	"roots":     {133, 133},
+	"^":         {134, 134},
+	"sqrt":      {135, 135},
+	"sin":       {136, 138},
+	"cos":       {136, 138},
+	"tan":       {136, 138},
+	"sinh":      {139, 139},
+	"cosh":      {140, 140},
+	"tanh":      {141, 141},
+	"asinh":     {142, 142},
+	"acosh":     {143, 143},
+	"atanh":     {144, 144},
+	"code":      {283, 283},
+	"char":      {284, 284},
+	"float":     {285, 285},
 }
 
 var helpBinary = map[string]helpIndexPair{
-	"+":      {76, 76},
-	"-":      {77, 77},
-	"*":      {78, 78},
-	"/":      {79, 81},
-	"**":     {82, 82},
-	"?":      {91, 91},
-	"in":     {92, 92},
-	"max":    {93, 93},
-	"min":    {94, 94},
-	"rho":    {95, 95},
-	"take":   {96, 96},
-	"drop":   {97, 97},
-	"decode": {98, 98},
-	"encode": {99, 99},
-	"mod":    {101, 102},
-	",":      {103, 103},
-	"fill":   {104, 105},
-	"sel":    {106, 107},
-	"iota":   {108, 109},
-	"rot":    {111, 111},
-	"flip":   {112, 112},
-	"log":    {113, 113},
-	"text":   {114, 118},
-	"!":      {120, 120},
-	"<":      {121, 121},
-	"<=":     {122, 122},
-	"==":     {123, 123},
-	">=":     {124, 124},
-	">":      {125, 125},
-	"!=":     {126, 126},
-	"or":     {127, 127},
-	"and":    {128, 128},
-	"nor":    {129, 129},
-	"nand":   {130, 130},
-	"xor":    {131, 131},
-	"&":      {132, 132},
-	"|":      {133, 133},
-	"^":      {134, 134},
-	"<<":     {135, 135},
-	">>":     {136, 136},
+	"+":         {149, 149},
+	"-":         {150, 150},
+	"*":         {151, 151},
+	"/":         {152, 154},
+	"**":        {155, 155},
+	"root":      {156, 156},
+	"?":         {165, 165},
+	"in":        {166, 166},
+	"count":     {167, 167},
+	"union":     {168, 168},
+	"intersect": {169, 169},
+	"diff":      {170, 170},
+	"max":       {171, 171},
+	"min":       {172, 172},
+	"round":     {173, 173},
+	"ceil":      {174, 174},
+	"floor":     {175, 175},
+	"approx":    {176, 176},
+	"rho":       {177, 178},
+	"take":      {179, 181},
+	"drop":      {182, 183},
+	"decode":    {184, 184},
+	"polyval":   {185, 186},
+	"encode":    {187, 187},
+	"mod":       {189, 190},
+	"gcd":       {191, 191},
+	"lcm":       {192, 192},
+	"powmod":    {193, 193},
+	",":         {194, 194},
+	"fill":      {195, 197},
+	"sel":       {198, 200},
+	"select":    {201, 202},
+	"iota":      {203, 204},
+	"inv":       {205, 206},
+	"dot":       {207, 207},
+	"cross":     {208, 208},
+	"kron":      {209, 210},
+	"mpow":      {211, 212},
+	"up":        {213, 214},
+	"down":      {215, 215},
+	"rot":       {216, 216},
+	"flip":      {217, 217},
+	"log":       {218, 219},
+	"text":      {220, 225},
+	"setenv":    {226, 226},
+	"match":     {227, 228},
+	"find":      {229, 230},
+	"csv":       {231, 232},
+	"transp":    {233, 234},
+	"!":         {235, 236},
+	"<":         {237, 237},
+	"<=":        {238, 238},
+	"==":        {239, 239},
+	">=":        {240, 240},
+	">":         {241, 241},
+	"!=":        {242, 242},
+	"or":        {243, 243},
+	"and":       {244, 244},
+	"nor":       {245, 245},
+	"nand":      {246, 246},
+	"xor":       {247, 247},
+	"&":         {248, 248},
+	"|":         {249, 249},
+	"^":         {250, 250},
+	"<<":        {251, 252},
+	">>":        {253, 254},
 }
 
 var helpAxis = map[string]helpIndexPair{
-	"/":  {141, 141},
-	"\\": {143, 143},
-	".":  {145, 145},
-	"o.": {146, 146},
+	"/":    {259, 259},
+	"/%":   {262, 262},
+	"\\":   {263, 263},
+	"\\%":  {264, 264},
+	".":    {265, 265},
+	"o.":   {266, 266},
+	"sum":  {269, 269},
+	"prod": {270, 270},
+	"[":    {272, 272},
 }
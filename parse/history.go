@@ -0,0 +1,45 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"strings"
+
+	"robpike.io/ivy/scan"
+)
+
+// remember adds the line just read to the history, unless it is
+// itself a )history command.
+func (p *Parser) remember() {
+	if len(p.tokens) == 0 {
+		return
+	}
+	if len(p.tokens) > 1 && p.tokens[0].Type == scan.RightParen && p.tokens[1].Text == "history" {
+		return
+	}
+	// The original text is gone, but the tokens recreate it.
+	text := make([]string, len(p.tokens))
+	for i, tok := range p.tokens {
+		text[i] = tok.Text
+	}
+	p.addHistory(strings.Join(text, " "))
+}
+
+// addHistory appends the line to the history, discarding the
+// oldest entries if the history is full.
+func (p *Parser) addHistory(line string) {
+	p.history = append(p.history, line)
+	p.trimHistory()
+}
+
+// trimHistory discards the oldest entries to bring the history
+// down to its configured size.
+func (p *Parser) trimHistory() {
+	size := int(p.context.Config().HistorySize())
+	if extra := len(p.history) - size; extra > 0 {
+		p.history = append(p.history[:0], p.history[extra:]...)
+		p.historyDropped += extra
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"time"
 
+	"robpike.io/ivy/config"
 	"robpike.io/ivy/exec"
 	"robpike.io/ivy/scan"
 	"robpike.io/ivy/value"
@@ -23,6 +25,14 @@ func tree(e interface{}) string {
 		return fmt.Sprintf("<bigint %s>", e)
 	case value.BigRat:
 		return fmt.Sprintf("<rat %s>", e)
+	case value.Complex:
+		return fmt.Sprintf("<complex %s>", e)
+	case value.Char:
+		return fmt.Sprintf("<char %s>", e.ProgString())
+	case value.BigFloat:
+		return fmt.Sprintf("<float %s>", e)
+	case value.Value:
+		return fmt.Sprintf("<%s>", e.ProgString())
 	case sliceExpr:
 		s := "<"
 		for i, x := range e {
@@ -36,12 +46,20 @@ func tree(e interface{}) string {
 	case variableExpr:
 		return fmt.Sprintf("<var %s>", e.name)
 	case *unary:
+		if e.axis != nil {
+			return fmt.Sprintf("(%s[%s] %s)", e.op, tree(e.axis), tree(e.right))
+		}
 		return fmt.Sprintf("(%s %s)", e.op, tree(e.right))
+	case *rangeReduce:
+		return fmt.Sprintf("(%s %s %s)", e.op, e.reduce, tree(e.right))
 	case *binary:
 		// Special case for [].
 		if e.op == "[]" {
 			return fmt.Sprintf("(%s[%s])", tree(e.left), tree(e.right))
 		}
+		if e.axis != nil {
+			return fmt.Sprintf("(%s %s[%s] %s)", tree(e.left), e.op, tree(e.axis), tree(e.right))
+		}
 		return fmt.Sprintf("(%s %s %s)", tree(e.left), e.op, tree(e.right))
 	case []value.Expr:
 		if len(e) == 1 {
@@ -61,6 +79,18 @@ func tree(e interface{}) string {
 	}
 }
 
+// timing reports whether to print the time taken by each operator
+// applied at top level, outside user-defined operators.
+func timing(context value.Context) bool {
+	return context.Config().Debug("time") && len(context.(*exec.Context).Stack) == 1
+}
+
+// printTime prints, for the "time" debug flag, the time d taken to
+// apply op and the type and shape of its result.
+func printTime(context value.Context, op string, d time.Duration, result value.Value) {
+	fmt.Fprintf(context.Config().Output(), "%s: %s %s\n", op, config.FormatDuration(d), typeAndShape(result))
+}
+
 // sliceExpr holds a syntactic vector to be verified and evaluated.
 type sliceExpr []value.Expr
 
@@ -151,6 +181,9 @@ type variableExpr struct {
 func (e variableExpr) Eval(context value.Context) value.Value {
 	v := context.Lookup(e.name)
 	if v == nil {
+		if fn, ok := value.NiladicOps[e.name]; ok {
+			return fn(context)
+		}
 		value.Errorf("undefined variable %q", e.name)
 	}
 	return v
@@ -164,7 +197,7 @@ func (e variableExpr) ProgString() string {
 // may require parentheses around it when printed to maintain correct evaluation order.
 func isCompound(x interface{}) bool {
 	switch x.(type) {
-	case value.Char, value.Int, value.BigInt, value.BigRat, value.BigFloat, value.Vector, value.Matrix:
+	case value.Char, value.Int, value.BigInt, value.BigRat, value.BigFloat, value.Complex, value.Vector, value.Matrix:
 		return false
 	case sliceExpr, variableExpr:
 		return false
@@ -173,21 +206,144 @@ func isCompound(x interface{}) bool {
 	}
 }
 
+// position records where an operator appears in the input.
+type position struct {
+	file string
+	line int
+}
+
+// String returns the position in the form "name:line", or the
+// empty string if the input is <stdin>, as with Parser.Loc.
+func (p position) String() string {
+	if p.file == "<stdin>" || p.file == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", p.file, p.line)
+}
+
+// annotate is deferred by the evaluation of an operator to fill in the
+// details of an error raised while applying it, unless an operator
+// within, such as one in the body of a user-defined operator, has
+// already done so. For a unary operator, left is nil.
+func annotate(pos position, op string, left, right value.Value) {
+	err := recover()
+	if err == nil {
+		return
+	}
+	if e, ok := err.(value.Error); ok && e.Op == "" {
+		e.Op = op
+		switch {
+		case op == "[]":
+			e.Operand = fmt.Sprintf("%s[%s]", typeAndShape(left), typeAndShape(right))
+		case left == nil:
+			e.Operand = op + " " + typeAndShape(right)
+		default:
+			e.Operand = typeAndShape(left) + " " + op + " " + typeAndShape(right)
+		}
+		e.Pos = pos.String()
+		err = e
+	}
+	panic(err)
+}
+
 type unary struct {
 	op    string
+	pos   position
+	axis  value.Expr // The axis, as in rot[1] x, or nil.
 	right value.Expr
 }
 
 func (u *unary) ProgString() string {
+	if u.axis != nil {
+		return fmt.Sprintf("%s[%s] %s", u.op, u.axis.ProgString(), u.right.ProgString())
+	}
 	return fmt.Sprintf("%s %s", u.op, u.right.ProgString())
 }
 
 func (u *unary) Eval(context value.Context) value.Value {
-	return context.EvalUnary(u.op, u.right.Eval(context).Inner())
+	if !timing(context) {
+		return u.eval(context)
+	}
+	start := time.Now()
+	v := u.eval(context)
+	printTime(context, u.op, time.Since(start), v)
+	return v
+}
+
+func (u *unary) eval(context value.Context) value.Value {
+	right := u.right.Eval(context).Inner()
+	if u.axis != nil {
+		conf := context.Config()
+		right = value.Materialize(conf, right)
+		axis := value.Materialize(conf, u.axis.Eval(context).Inner())
+		defer annotate(u.pos, u.op, nil, right)
+		return value.Reverse(context, u.op, right, axis)
+	}
+	defer annotate(u.pos, u.op, nil, right)
+	return context.EvalUnary(u.op, right)
+}
+
+// rangeReduce is a reduction of a unary operator over a range of
+// integers, as in f sum 1 1000. The operator is applied to each
+// integer in turn, so the range is never materialized.
+type rangeReduce struct {
+	op     string // The operator applied to each integer.
+	reduce string // "sum" or "prod".
+	right  value.Expr
+}
+
+// rangeReduceOps maps the names of the range reductions to the
+// binary operators they accumulate with.
+var rangeReduceOps = map[string]string{
+	"sum":  "+",
+	"prod": "*",
+}
+
+func (r *rangeReduce) ProgString() string {
+	return fmt.Sprintf("%s %s %s", r.op, r.reduce, r.right.ProgString())
+}
+
+func (r *rangeReduce) Eval(context value.Context) value.Value {
+	if !timing(context) {
+		return r.eval(context)
+	}
+	start := time.Now()
+	v := r.eval(context)
+	printTime(context, r.op+" "+r.reduce, time.Since(start), v)
+	return v
+}
+
+func (r *rangeReduce) eval(context value.Context) value.Value {
+	lo, hi := rangeBounds(r.reduce, value.Materialize(context.Config(), r.right.Eval(context).Inner()))
+	var acc value.Value = value.Int(0)
+	if r.reduce == "prod" {
+		acc = value.Int(1)
+	}
+	op := rangeReduceOps[r.reduce]
+	for i := lo; i <= hi; i++ {
+		acc = context.EvalBinary(acc, op, context.EvalUnary(r.op, value.Int(i)))
+	}
+	return acc
+}
+
+// rangeBounds returns the bounds of the range v, which must be two
+// small integers.
+func rangeBounds(name string, v value.Value) (lo, hi int64) {
+	if vec, ok := v.(value.Vector); ok && len(vec) == 2 {
+		lo, lok := vec[0].(value.Int)
+		hi, hok := vec[1].(value.Int)
+		if lok && hok {
+			return int64(lo), int64(hi)
+		}
+	}
+	value.Errorf("%s: range must be two small integers", name)
+	return
 }
 
 type binary struct {
 	op    string
+	pos   position
+	axis  value.Expr // The axis, as in 1 rot[1] x, or nil.
 	left  value.Expr
 	right value.Expr
 }
@@ -203,6 +359,9 @@ func (b *binary) ProgString() string {
 	if b.op == "[]" {
 		return fmt.Sprintf("%s[%s]", left, b.right.ProgString())
 	}
+	if b.axis != nil {
+		return fmt.Sprintf("%s %s[%s] %s", left, b.op, b.axis.ProgString(), b.right.ProgString())
+	}
 	return fmt.Sprintf("%s %s %s", left, b.op, b.right.ProgString())
 }
 
@@ -210,8 +369,35 @@ func (b *binary) Eval(context value.Context) value.Value {
 	if b.op == "=" {
 		return assignment(context, b)
 	}
+	if !timing(context) {
+		return b.eval(context)
+	}
+	start := time.Now()
+	v := b.eval(context)
+	printTime(context, b.op, time.Since(start), v)
+	return v
+}
+
+func (b *binary) eval(context value.Context) value.Value {
 	rhs := b.right.Eval(context).Inner()
 	lhs := b.left.Eval(context)
+	if b.axis != nil {
+		// The operators that take an axis look inside their operands.
+		conf := context.Config()
+		lhs, rhs = value.Materialize(conf, lhs.Inner()), value.Materialize(conf, rhs)
+		axis := value.Materialize(conf, b.axis.Eval(context).Inner())
+		defer annotate(b.pos, b.op, lhs, rhs)
+		switch b.op {
+		case ",":
+			return value.Catenate(context, lhs.Inner(), rhs, axis)
+		case "sel":
+			return value.Replicate(context, lhs.Inner(), rhs, axis)
+		case "fill", "\\":
+			return value.Expand(context, b.op, lhs.Inner(), rhs, axis)
+		}
+		return value.Rotate(context, b.op, lhs.Inner(), rhs, axis)
+	}
+	defer annotate(b.pos, b.op, lhs, rhs)
 	return context.EvalBinary(lhs, b.op, rhs)
 }
 
@@ -222,7 +408,13 @@ type Parser struct {
 	tokenBuf [100]scan.Token // Reusable.
 	fileName string
 	lineNum  int
+	lines    int // Number of input lines read.
 	context  *exec.Context
+	// Whether to prompt for continuation lines.
+	interactive bool
+	// Recent input lines, for )history, and how many older ones are gone.
+	history        []string
+	historyDropped int
 }
 
 // NewParser returns a new parser that will read from the scanner.
@@ -235,6 +427,17 @@ func NewParser(fileName string, scanner *scan.Scanner, context value.Context) *P
 	}
 }
 
+// SetInteractive sets whether the input is interactive, in which
+// case the parser prompts for the continuation of incomplete lines.
+func (p *Parser) SetInteractive(interactive bool) {
+	p.interactive = interactive
+}
+
+// InputLine returns the number of the next line of input.
+func (p *Parser) InputLine() int {
+	return p.lines + 1
+}
+
 // Printf formats the args and writes them to the configured output writer.
 func (p *Parser) Printf(format string, args ...interface{}) {
 	fmt.Fprintf(p.context.Config().Output(), format, args...)
@@ -277,6 +480,22 @@ func (p *Parser) Loc() string {
 	return fmt.Sprintf("%s:%d: ", p.fileName, p.lineNum)
 }
 
+// pos returns the position of the token in the input.
+func (p *Parser) pos(tok scan.Token) position {
+	return position{p.fileName, tok.Line}
+}
+
+// ErrorString returns the error as reported to the user, with the
+// position of the operator that failed or, if that is unknown, the
+// current input location.
+func (p *Parser) ErrorString(err value.Error) string {
+	loc := p.Loc()
+	if err.Pos != "" {
+		loc = err.Pos + ": "
+	}
+	return loc + err.Detail()
+}
+
 func (p *Parser) errorf(format string, args ...interface{}) {
 	p.tokens = p.tokenBuf[:0]
 	value.Errorf(format, args...)
@@ -287,6 +506,7 @@ func (p *Parser) errorf(format string, args ...interface{}) {
 // The boolean reports whether the line is valid.
 //
 // Line
+//
 //	) special command '\n'
 //	def function defintion
 //	expressionList '\n'
@@ -295,6 +515,7 @@ func (p *Parser) Line() ([]value.Expr, bool) {
 	if !p.readTokensToNewline() {
 		return nil, false
 	}
+	p.remember()
 	tok := p.peek()
 	switch tok.Type {
 	case scan.EOF:
@@ -319,23 +540,60 @@ func (p *Parser) Line() ([]value.Expr, bool) {
 // We read all tokens before parsing for easy error recovery
 // if an error occurs mid-line. It also gives us lookahead
 // for parsing, which we may use one day.
+// If the line is incomplete, because it ends with an operator
+// or opening bracket, it continues on the next line. A blank
+// line or EOF abandons an incomplete line.
 func (p *Parser) readTokensToNewline() bool {
 	p.tokens = p.tokenBuf[:0]
+	continued := false
+	blank := false
 	for {
 		tok := p.scanner.Next()
 		switch tok.Type {
 		case scan.Error:
 			p.errorf("%s", tok)
 		case scan.Newline:
-			return true
+			p.lines++
+			if blank {
+				p.tokens = p.tokenBuf[:0]
+				return true
+			}
+			if !p.incomplete() {
+				return true
+			}
+			if p.interactive {
+				p.Printf("%s", p.context.Config().Prompt2())
+			}
+			continued, blank = true, true
+			continue
 		case scan.EOF:
+			if continued {
+				p.tokens = p.tokenBuf[:0]
+				return false
+			}
 			return len(p.tokens) > 0
 		}
+		blank = false
 		p.tokens = append(p.tokens, tok)
 	}
 }
 
+// incomplete reports whether the tokens read so far end with an
+// operator or opening bracket, and so continue on the next line.
+// Special commands are always complete.
+func (p *Parser) incomplete() bool {
+	if len(p.tokens) == 0 || p.tokens[0].Type == scan.RightParen {
+		return false
+	}
+	switch p.tokens[len(p.tokens)-1].Type {
+	case scan.Operator, scan.LeftParen, scan.LeftBrack:
+		return true
+	}
+	return false
+}
+
 // expressionList:
+//
 //	statementList <eol>
 func (p *Parser) expressionList() ([]value.Expr, bool) {
 	exprs, ok := p.statementList()
@@ -355,6 +613,7 @@ func (p *Parser) expressionList() ([]value.Expr, bool) {
 }
 
 // statementList:
+//
 //	expr
 //	expr ';' expr
 func (p *Parser) statementList() ([]value.Expr, bool) {
@@ -374,6 +633,7 @@ func (p *Parser) statementList() ([]value.Expr, bool) {
 }
 
 // expr
+//
 //	operand
 //	operand binop expr
 func (p *Parser) expr() value.Expr {
@@ -392,6 +652,8 @@ func (p *Parser) expr() value.Expr {
 			return &binary{
 				left:  expr,
 				op:    tok.Text,
+				pos:   p.pos(tok),
+				axis:  p.axis(tok.Text, true),
 				right: p.expr(),
 			}
 		}
@@ -417,6 +679,8 @@ func (p *Parser) expr() value.Expr {
 		return &binary{
 			left:  expr,
 			op:    tok.Text,
+			pos:   p.pos(tok),
+			axis:  p.axis(tok.Text, true),
 			right: p.expr(),
 		}
 	}
@@ -425,6 +689,7 @@ func (p *Parser) expr() value.Expr {
 }
 
 // operand
+//
 //	number
 //	char constant
 //	string constant
@@ -435,14 +700,24 @@ func (p *Parser) operand(tok scan.Token, indexOK bool) value.Expr {
 	var expr value.Expr
 	switch tok.Type {
 	case scan.Operator:
+		if expr = p.rangeReduce(tok); expr != nil {
+			break
+		}
 		expr = &unary{
 			op:    tok.Text,
+			pos:   p.pos(tok),
+			axis:  p.axis(tok.Text, false),
 			right: p.expr(),
 		}
 	case scan.Identifier:
 		if p.context.DefinedUnary(tok.Text) {
+			if expr = p.rangeReduce(tok); expr != nil {
+				break
+			}
 			expr = &unary{
 				op:    tok.Text,
+				pos:   p.pos(tok),
+				axis:  p.axis(tok.Text, false),
 				right: p.expr(),
 			}
 			break
@@ -459,13 +734,78 @@ func (p *Parser) operand(tok scan.Token, indexOK bool) value.Expr {
 	return expr
 }
 
+// rangeReduce
+//
+//	unop sum expr
+//	unop prod expr
+//
+// It returns nil if the unary operator tok is not followed by sum or
+// prod, or if the user has defined a variable or operator with that name.
+func (p *Parser) rangeReduce(tok scan.Token) value.Expr {
+	next := p.peek()
+	if next.Type != scan.Identifier || rangeReduceOps[next.Text] == "" {
+		return nil
+	}
+	if p.context.Lookup(next.Text) != nil || p.context.DefinedUnary(next.Text) || p.context.DefinedBinary(next.Text) {
+		return nil
+	}
+	p.next()
+	return &rangeReduce{
+		op:     tok.Text,
+		reduce: next.Text,
+		right:  p.expr(),
+	}
+}
+
+// unaryAxisOps and binaryAxisOps hold the builtin operators that
+// accept an axis.
+var (
+	unaryAxisOps = map[string]bool{
+		"rot":  true,
+		"flip": true,
+	}
+	binaryAxisOps = map[string]bool{
+		",":    true,
+		"rot":  true,
+		"flip": true,
+		"sel":  true,
+		"fill": true,
+		"\\":   true,
+	}
+)
+
+// axis
+//
+//	'[' expr ']'
+//
+// The axis follows the operator, as in rot[1] x. Only the builtin
+// operators in unaryAxisOps or binaryAxisOps accept one; it returns nil
+// for other operators, or if there is no axis.
+func (p *Parser) axis(op string, isBinary bool) value.Expr {
+	ops := unaryAxisOps
+	if isBinary {
+		ops = binaryAxisOps
+	}
+	if p.peek().Type != scan.LeftBrack || !ops[op] || p.context.UserDefined(op, false) || p.context.UserDefined(op, true) {
+		return nil
+	}
+	p.next()
+	axis := p.expr()
+	tok := p.next()
+	if tok.Type != scan.RightBrack {
+		p.errorf("expected right bracket, found %s", tok)
+	}
+	return axis
+}
+
 // index
+//
 //	expr
 //	expr [ expr ]
 //	expr [ expr ] [ expr ] ....
 func (p *Parser) index(expr value.Expr) value.Expr {
 	for p.peek().Type == scan.LeftBrack {
-		p.next()
+		pos := p.pos(p.next())
 		index := p.expr()
 		tok := p.next()
 		if tok.Type != scan.RightBrack {
@@ -473,6 +813,7 @@ func (p *Parser) index(expr value.Expr) value.Expr {
 		}
 		expr = &binary{
 			op:    "[]",
+			pos:   pos,
 			left:  expr,
 			right: index,
 		}
@@ -481,11 +822,13 @@ func (p *Parser) index(expr value.Expr) value.Expr {
 }
 
 // number
+//
 //	integer
 //	rational
 //	string
 //	variable
 //	'(' Expr ')'
+//
 // If the value is a string, value.Expr is nil.
 func (p *Parser) number(tok scan.Token) (expr value.Expr, str string) {
 	var err error
@@ -496,7 +839,7 @@ func (p *Parser) number(tok scan.Token) (expr value.Expr, str string) {
 	case scan.String:
 		str = value.ParseString(text)
 	case scan.Number, scan.Rational:
-		expr, err = value.Parse(p.context.Config(), text)
+		expr, err = value.ParseLiteral(p.context.Config(), text)
 	case scan.LeftParen:
 		expr = p.expr()
 		tok := p.next()
@@ -512,6 +855,7 @@ func (p *Parser) number(tok scan.Token) (expr value.Expr, str string) {
 
 // numberOrVector turns the token and what follows into a numeric Value, possibly a vector.
 // numberOrVector
+//
 //	number
 //	string
 //	numberOrVector...
@@ -54,12 +54,27 @@ Mutually recursive functions are an extra wrinkle but easy to resolve.
 
 // save writes the state of the workspace to the named file.
 // The format of the output is ivy source text.
-func save(c *exec.Context, file string) {
+// If names is non-empty, only the named operators and variables
+// are saved, along with any operators they depend on, and the
+// configuration settings are not. If appending is set, the output
+// is added to the end of the file rather than replacing it.
+func save(c *exec.Context, file string, appending bool, names []string) {
+	// Resolve the names first so an error does not truncate the file.
+	var defs map[exec.OpDef]bool
+	var vars map[string]bool
+	if len(names) > 0 {
+		defs, vars = saveSelection(c, names)
+	}
+
 	// "<conf.out>" is a special case for testing.
 	conf := c.Config()
 	out := conf.Output()
 	if file != "<conf.out>" {
-		fd, err := os.Create(file)
+		flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if appending {
+			flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		fd, err := os.OpenFile(file, flag, 0666)
 		if err != nil {
 			value.Errorf("%s", err)
 		}
@@ -71,18 +86,39 @@ func save(c *exec.Context, file string) {
 
 	// Configuration settings. We will set the base below,
 	// after we have printed all numbers in base 10.
-	fmt.Fprintf(out, ")prec %d\n", conf.FloatPrec())
 	ibase, obase := conf.Base()
-	fmt.Fprintf(out, ")maxbits %d\n", conf.MaxBits())
-	fmt.Fprintf(out, ")maxdigits %d\n", conf.MaxDigits())
-	fmt.Fprintf(out, ")origin %d\n", conf.Origin())
-	fmt.Fprintf(out, ")prompt %q\n", conf.Prompt())
-	fmt.Fprintf(out, ")format %q\n", conf.Format())
+	if names == nil {
+		fmt.Fprintf(out, ")prec %d\n", conf.FloatPrec())
+		fmt.Fprintf(out, ")maxbits %d\n", conf.MaxBits())
+		fmt.Fprintf(out, ")maxdigits %d\n", conf.MaxDigits())
+		fmt.Fprintf(out, ")origin %d\n", conf.Origin())
+		fmt.Fprintf(out, ")prompt %q\n", conf.Prompt())
+		fmt.Fprintf(out, ")prompt2 %q\n", conf.Prompt2())
+		fmt.Fprintf(out, ")format %q\n", conf.Format())
+		for _, typ := range config.FormatTypes {
+			if f := conf.TypeFormat(typ); f != "" {
+				fmt.Fprintf(out, ")format %s %q\n", typ, f)
+			}
+		}
+		if _, _, ok := conf.Notation(); ok {
+			fmt.Fprintf(out, ")format %s\n", notation(conf))
+		}
+		if conf.RationalDecimal() {
+			fmt.Fprintf(out, ")format rational off\n")
+		}
+		fmt.Fprintf(out, ")width %d\n", conf.OutputWidth())
+		if sep, size := conf.Grouping(); sep != "" {
+			fmt.Fprintf(out, ")grouping %q %d\n", sep, size)
+		}
+	}
 	conf.SetBase(10, 10)
 
 	// Ops.
 	printed := make(map[exec.OpDef]bool)
 	for _, def := range c.Defs {
+		if defs != nil && !defs[def] {
+			continue
+		}
 		var fn *exec.Function
 		if def.IsBinary {
 			fn = c.BinaryFn[def.Name]
@@ -105,7 +141,7 @@ func save(c *exec.Context, file string) {
 
 	// Global variables.
 	syms := c.Stack[0]
-	if len(syms) > 0 {
+	if len(syms) > 0 && (vars == nil || len(vars) > 0) {
 		// Set the base strictly to 10 for output.
 		fmt.Fprintf(out, "# Set base 10 for parsing numbers.\n)base 10\n")
 		// Sort the names for consistent output.
@@ -115,6 +151,9 @@ func save(c *exec.Context, file string) {
 			if sym.name == "pi" || sym.name == "e" {
 				continue
 			}
+			if vars != nil && !vars[sym.name] {
+				continue
+			}
 			fmt.Fprintf(out, "%s = ", sym.name)
 			put(conf, out, sym.val)
 			fmt.Fprint(out, "\n")
@@ -129,6 +168,47 @@ func save(c *exec.Context, file string) {
 	conf.SetBase(ibase, obase)
 }
 
+// saveSelection returns the set of operator definitions and variables
+// to be saved for the named items. The operators include everything
+// the named ones depend on, so the saved file can be read back alone.
+func saveSelection(c *exec.Context, names []string) (map[exec.OpDef]bool, map[string]bool) {
+	defs := make(map[exec.OpDef]bool)
+	vars := make(map[string]bool)
+	var add func(def exec.OpDef)
+	add = func(def exec.OpDef) {
+		if defs[def] {
+			return
+		}
+		defs[def] = true
+		fn := c.UnaryFn[def.Name]
+		if def.IsBinary {
+			fn = c.BinaryFn[def.Name]
+		}
+		for _, ref := range references(c, fn.Body) {
+			add(ref)
+		}
+	}
+	for _, name := range names {
+		found := false
+		if c.UnaryFn[name] != nil {
+			add(exec.OpDef{Name: name, IsBinary: false})
+			found = true
+		}
+		if c.BinaryFn[name] != nil {
+			add(exec.OpDef{Name: name, IsBinary: true})
+			found = true
+		}
+		if c.Stack[0][name] != nil && name != "pi" && name != "e" {
+			vars[name] = true
+			found = true
+		}
+		if !found {
+			value.Errorf(")save: %q not defined", name)
+		}
+	}
+	return defs, vars
+}
+
 // saveSym holds a variable's name and value so we can sort them for saving.
 type saveSym struct {
 	name string
@@ -175,6 +255,16 @@ func put(conf *config.Config, out io.Writer, val value.Value) {
 		// Probably not important but it would be nice to fix it.
 		digits := int(float64(val.Prec()) * 0.301029995664) // 10 log 2.
 		fmt.Fprintf(out, "%.*g", digits+1, val.Float)       // Add another digit to be sure.
+	case value.Complex:
+		put(conf, out, val.Real())
+		fmt.Fprint(out, "j")
+		put(conf, out, val.Imag())
+	case value.Time:
+		fmt.Fprint(out, val.ProgString())
+	case value.Box:
+		fmt.Fprint(out, "(enclose ")
+		put(conf, out, val.Contents())
+		fmt.Fprint(out, ")")
 	case value.Vector:
 		if val.AllChars() {
 			fmt.Fprintf(out, "%q", val.Sprint(conf))
@@ -186,6 +276,8 @@ func put(conf *config.Config, out io.Writer, val value.Value) {
 			}
 			put(conf, out, v)
 		}
+	case value.Range:
+		put(conf, out, value.Materialize(conf, val))
 	case *value.Matrix:
 		put(conf, out, value.NewIntVector(val.Shape()))
 		fmt.Fprint(out, " rho ")
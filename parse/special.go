@@ -9,11 +9,17 @@ package parse // import "robpike.io/ivy/parse"
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"robpike.io/ivy/config"
 	"robpike.io/ivy/scan"
@@ -22,6 +28,20 @@ import (
 
 const defaultFile = "save.ivy"
 
+// minOutputWidth is the narrowest line width allowed by )width.
+const minOutputWidth = 10
+
+// defaultGroupSize is the number of digits in a group if )grouping
+// does not specify one.
+const defaultGroupSize = 3
+
+// badSeparator reports whether r may not appear in a digit grouping
+// separator, because it would be confused with part of a number or
+// break the alignment of printed matrices.
+func badSeparator(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || strings.ContainsRune(".-/", r)
+}
+
 func (p *Parser) need(want ...scan.Type) scan.Token {
 	tok := p.next()
 	for _, w := range want {
@@ -47,6 +67,17 @@ func (p *Parser) nextDecimalNumber() int {
 	return int(n64)
 }
 
+// nextSignedDecimalNumber returns the next number, which must
+// be an integer, possibly negative, that fits in an int32.
+func (p *Parser) nextSignedDecimalNumber() int {
+	tok := p.need(scan.Number)
+	n, err := strconv.ParseInt(tok.Text, 10, 32)
+	if err != nil {
+		p.errorf("expected integer, got %s", tok)
+	}
+	return int(n)
+}
+
 // nextDecimalNumber64 returns the next number, which
 // must fit in a non-negative int64.
 func (p *Parser) nextDecimalNumber64() int64 {
@@ -54,7 +85,7 @@ func (p *Parser) nextDecimalNumber64() int64 {
 	ibase, obase := conf.Base()
 	defer conf.SetBase(ibase, obase)
 	conf.SetBase(10, obase)
-	v, err := value.Parse(conf, p.need(scan.Number).Text)
+	v, err := value.ParseLiteral(conf, p.need(scan.Number).Text)
 	if err != nil {
 		p.errorf("%s", err)
 	}
@@ -92,6 +123,9 @@ func (p *Parser) special() {
 	ibase, obase := conf.Base()
 	defer func() {
 		conf.SetBase(ibase, obase)
+		// The command may have changed the configuration, on which
+		// the results of memoized operators may depend.
+		p.context.ForgetMemos()
 	}()
 	conf.SetBase(0, 0)
 Switch:
@@ -145,7 +179,10 @@ Switch:
 			p.Printf("obase\t%d\n", obase)
 			break Switch
 		}
-		base := p.nextDecimalNumber()
+		base := 0
+		if !p.isDefault() {
+			base = p.nextDecimalNumber()
+		}
 		if base != 0 && (base < 2 || 36 < base) {
 			p.errorf("illegal base %d", base)
 		}
@@ -157,6 +194,50 @@ Switch:
 		case "obase":
 			obase = base
 		}
+	case "cd":
+		var dir string
+		if p.peek().Type == scan.EOF {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				p.errorf("%s", err)
+			}
+			dir = home
+		} else {
+			dir = p.getString()
+		}
+		if err := os.Chdir(dir); err != nil {
+			p.errorf("%s", err)
+		}
+	case "clear":
+		what := "all"
+		if p.peek().Type != scan.EOF {
+			what = p.need(scan.Identifier).Text
+		}
+		switch what {
+		case "all":
+			p.context.ClearVars()
+			p.context.ClearOps()
+			ibase, obase = 0, 0
+			conf.SetFormat("")
+			for _, typ := range config.FormatTypes {
+				conf.SetTypeFormat(typ, "")
+			}
+			conf.SetRationalDecimal(false)
+			conf.ResetNotation()
+			conf.SetOrigin(1)
+		case "vars":
+			p.context.ClearVars()
+		case "ops":
+			p.context.ClearOps()
+		default:
+			p.errorf(")clear: unknown argument %q", what)
+		}
+	case "color":
+		if p.peek().Type == scan.EOF {
+			p.Println(onOff(conf.Color()))
+			break Switch
+		}
+		conf.SetColor(p.needOnOff(")color"))
 	case "cpu":
 		p.Printf("%s\n", conf.PrintCPUTime())
 	case "debug":
@@ -184,27 +265,170 @@ Switch:
 			p.Println("no such debug flag:", name)
 		}
 	case "demo":
-		p.need(scan.EOF)
-		cmd := exec.Command("go", "run", pathTo("demo.go"))
+		args := []string{"run", pathTo("demo.go")}
+		if p.peek().Type != scan.EOF {
+			name := p.need(scan.Identifier).Text
+			switch {
+			case exists(pathTo(name + ".go")):
+				args = []string{"run", pathTo(name + ".go")}
+			case exists(pathTo(name + ".ivy")):
+				args = append(args, pathTo(name+".ivy"))
+			default:
+				p.errorf(")demo: no demo %q", name)
+			}
+		}
+		cmd := exec.Command("go", args...)
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = conf.Output()
-		cmd.Stdout = conf.ErrOutput()
+		cmd.Stderr = conf.ErrOutput()
 		err := cmd.Run()
 		if err != nil {
 			p.errorf("%v", err)
 		}
 	case "format":
+		if tok := p.peek(); tok.Type == scan.Identifier {
+			switch tok.Text {
+			case "sci":
+				p.next()
+				conf.SetNotation(0, 0)
+				break Switch
+			case "fixed":
+				p.next()
+				conf.SetNotation(math.MinInt32, math.MaxInt32)
+				break Switch
+			case "auto":
+				p.next()
+				if p.peek().Type == scan.EOF {
+					conf.ResetNotation()
+					break Switch
+				}
+				low := p.nextSignedDecimalNumber()
+				high := p.nextSignedDecimalNumber()
+				if low > high {
+					p.errorf(")format auto: bad exponent range %d %d", low, high)
+				}
+				conf.SetNotation(low, high)
+				break Switch
+			}
+		}
+		if tok := p.peek(); tok.Type == scan.Identifier && tok.Text == "rational" {
+			p.next()
+			if p.peek().Type == scan.EOF {
+				p.Println(onOff(!conf.RationalDecimal()))
+				break Switch
+			}
+			conf.SetRationalDecimal(!p.needOnOff(")format rational"))
+			break Switch
+		}
+		if typ := p.formatType(); typ != "" {
+			if p.peek().Type == scan.EOF {
+				p.Printf("%q\n", conf.TypeFormat(typ))
+				break Switch
+			}
+			if p.isDefault() {
+				conf.SetTypeFormat(typ, "")
+				break Switch
+			}
+			conf.SetTypeFormat(typ, p.getString())
+			break Switch
+		}
 		if p.peek().Type == scan.EOF {
 			p.Printf("%q\n", conf.Format())
+			for _, typ := range config.FormatTypes {
+				if f := conf.TypeFormat(typ); f != "" {
+					p.Printf("%s %q\n", typ, f)
+				}
+			}
+			if _, _, ok := conf.Notation(); ok {
+				p.Println(notation(conf))
+			}
+			break Switch
+		}
+		if p.isDefault() {
+			conf.SetFormat("")
 			break Switch
 		}
 		conf.SetFormat(p.getString())
+	case "dateformat":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%q\n", conf.DateFormat())
+			break Switch
+		}
+		if p.isDefault() {
+			conf.SetDateFormat("")
+			break Switch
+		}
+		conf.SetDateFormat(p.getString())
 	case "get":
 		if p.peek().Type == scan.EOF {
 			p.runFromFile(p.context, defaultFile)
 		} else {
 			p.runFromFile(p.context, p.getString())
 		}
+	case "grouping":
+		if p.peek().Type == scan.EOF {
+			sep, size := conf.Grouping()
+			if sep == "" {
+				p.Println(`""`)
+			} else {
+				p.Printf("%q %d\n", sep, size)
+			}
+			break Switch
+		}
+		if p.isDefault() {
+			conf.SetGrouping("", 0)
+			break Switch
+		}
+		sep := p.getString()
+		size := defaultGroupSize
+		if p.peek().Type != scan.EOF {
+			size = p.nextDecimalNumber()
+		}
+		if strings.IndexFunc(sep, badSeparator) >= 0 {
+			p.errorf("illegal grouping separator %q", sep)
+		}
+		if size <= 0 {
+			p.errorf("illegal group size %d", size)
+		}
+		conf.SetGrouping(sep, size)
+	case "history":
+		if p.peek().Type == scan.EOF {
+			for i, line := range p.history {
+				p.Printf("%d\t%s\n", p.historyDropped+i+1, line)
+			}
+			break Switch
+		}
+		if tok := p.peek(); tok.Type == scan.Identifier && tok.Text == "size" {
+			p.next()
+			if p.peek().Type == scan.EOF {
+				p.Println(conf.HistorySize())
+				break Switch
+			}
+			conf.SetHistorySize(uint(p.nextDecimalNumber()))
+			p.trimHistory()
+			break Switch
+		}
+		n := p.nextDecimalNumber()
+		i := n - p.historyDropped - 1
+		if i < 0 || len(p.history) <= i {
+			p.errorf(")history: no entry %d", n)
+		}
+		line := p.history[i]
+		p.Println(line)
+		p.addHistory(line)
+		conf.SetBase(ibase, obase)
+		p.runFromReader(p.context, "<history>", strings.NewReader(line+"\n"))
+	case "intbits":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%d\n", conf.IntBits())
+			break Switch
+		}
+		bits := p.nextDecimalNumber()
+		// One bit could not hold the 1 of a true comparison.
+		if bits == 1 || bits > 64 {
+			p.errorf("illegal intbits %d", bits)
+		}
+		conf.SetIntBits(uint(bits))
 	case "maxbits":
 		if p.peek().Type == scan.EOF {
 			p.Printf("%d\n", conf.MaxBits())
@@ -212,6 +436,13 @@ Switch:
 		}
 		max := p.nextDecimalNumber()
 		conf.SetMaxBits(uint(max))
+	case "maxdepth":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%d\n", conf.MaxDepth())
+			break Switch
+		}
+		max := p.nextDecimalNumber()
+		conf.SetMaxDepth(uint(max))
 	case "maxdigits":
 		if p.peek().Type == scan.EOF {
 			p.Printf("%d\n", conf.MaxDigits())
@@ -219,6 +450,30 @@ Switch:
 		}
 		max := p.nextDecimalNumber()
 		conf.SetMaxDigits(uint(max))
+	case "nan":
+		if p.peek().Type == scan.EOF {
+			p.Println(onOff(conf.NaN()))
+			break Switch
+		}
+		conf.SetNaN(p.needOnOff(")nan"))
+	case "parallel":
+		if p.peek().Type == scan.EOF {
+			p.Println(onOff(conf.Parallel()))
+			break Switch
+		}
+		conf.SetParallel(p.needOnOff(")parallel"))
+	case "shell":
+		if p.peek().Type == scan.EOF {
+			p.Println(onOff(conf.Shell()))
+			break Switch
+		}
+		conf.SetShell(p.needOnOff(")shell"))
+	case "warnoverflow":
+		if p.peek().Type == scan.EOF {
+			p.Println(onOff(conf.WarnOverflow()))
+			break Switch
+		}
+		conf.SetWarnOverflow(p.needOnOff(")warnoverflow"))
 	case "op", "ops": // We keep forgetting whether it's a plural or not.
 		if p.peek().Type == scan.EOF {
 			var unary, binary []string
@@ -266,6 +521,10 @@ Switch:
 			break Switch
 
 		}
+		if p.isDefault() {
+			conf.SetOrigin(1)
+			break Switch
+		}
 		origin := p.nextDecimalNumber()
 		if origin != 0 && origin != 1 {
 			p.errorf("illegal origin %d", origin)
@@ -283,24 +542,152 @@ Switch:
 		conf.SetFloatPrec(uint(prec))
 	case "prompt":
 		if p.peek().Type == scan.EOF {
-			p.Printf("%q\n", conf.Format())
+			p.Printf("%q\n", conf.Prompt())
 			break Switch
 		}
 		conf.SetPrompt(p.getString())
+	case "prompt2":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%q\n", conf.Prompt2())
+			break Switch
+		}
+		if p.isDefault() {
+			conf.SetPrompt2(config.DefaultPrompt2)
+			break Switch
+		}
+		conf.SetPrompt2(p.getString())
+	case "pwd":
+		dir, err := os.Getwd()
+		if err != nil {
+			p.errorf("%s", err)
+		}
+		p.Println(dir)
 	case "save":
 		// Must restore ibase, obase for safe.
 		conf.SetBase(ibase, obase)
-		if p.peek().Type == scan.EOF {
-			save(p.context, defaultFile)
-		} else {
-			save(p.context, p.getString())
+		appending := false
+		if tok := p.peek(); tok.Type == scan.Operator && tok.Text == "-" {
+			p.next()
+			if flag := p.need(scan.Identifier).Text; flag != "a" {
+				p.errorf(")save: unknown flag -%s", flag)
+			}
+			appending = true
+		}
+		file := defaultFile
+		if p.peek().Type == scan.String {
+			file = p.getString()
 		}
+		var names []string
+		for p.peek().Type != scan.EOF {
+			names = append(names, p.need(scan.Operator, scan.Identifier).Text)
+		}
+		save(p.context, file, appending, names)
 	case "seed":
 		if p.peek().Type == scan.EOF {
 			p.Println(conf.RandomSeed())
 			break Switch
 		}
 		conf.SetRandomSeed(p.nextDecimalNumber64())
+	case "timeout":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%g\n", conf.Timeout().Seconds())
+			break Switch
+		}
+		tok := p.need(scan.Number)
+		t, err := strconv.ParseFloat(tok.Text, 64)
+		if err != nil || t < 0 || t > math.MaxInt64/float64(time.Second) {
+			p.errorf("illegal timeout %s", tok.Text)
+		}
+		conf.SetTimeout(time.Duration(t * float64(time.Second)))
+	case "tolerance":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%g\n", conf.Tolerance())
+			break Switch
+		}
+		tok := p.need(scan.Number)
+		t, err := strconv.ParseFloat(tok.Text, 64)
+		if err != nil || t < 0 || t >= 1 {
+			p.errorf("illegal tolerance %s", tok.Text)
+		}
+		conf.SetTolerance(t)
+	case "undef":
+		name := p.need(scan.Operator, scan.Identifier).Text
+		unary, binary := true, true
+		if p.peek().Type != scan.EOF {
+			switch valence := p.need(scan.Identifier).Text; valence {
+			case "unary":
+				binary = false
+			case "binary":
+				unary = false
+			default:
+				p.errorf(")undef: unknown valence %q", valence)
+			}
+		}
+		found := false
+		if unary && p.context.Undefine(name, false) {
+			found = true
+		}
+		if binary && p.context.Undefine(name, true) {
+			found = true
+		}
+		if !found {
+			p.errorf(")undef: %q not defined", name)
+		}
+	case "time":
+		// The expression must be parsed and printed in the user's base.
+		conf.SetBase(ibase, obase)
+		exprs, _ := p.expressionList()
+		startCPU, cpuOK := cpuTime()
+		start := time.Now()
+		values := p.context.Eval(exprs)
+		conf.SetCPUTime(time.Since(start))
+		if cpuOK {
+			endCPU, _ := cpuTime()
+			p.Printf("real %s, cpu %s\n", conf.PrintCPUTime(), config.FormatDuration(endCPU-startCPU))
+		} else {
+			p.Printf("real %s\n", conf.PrintCPUTime())
+		}
+		var results []string
+		for _, v := range values {
+			if _, ok := v.(Assignment); ok {
+				continue
+			}
+			results = append(results, v.Sprint(conf))
+		}
+		if results != nil {
+			p.Println(strings.Join(results, " "))
+		}
+	case "var", "vars": // We keep forgetting whether it's a plural or not.
+		syms := p.context.Stack[0]
+		if p.peek().Type == scan.EOF {
+			for _, sym := range sortSyms(syms) {
+				// pi and e are generated
+				if sym.name == "pi" || sym.name == "e" {
+					continue
+				}
+				p.Printf("%s\t%s\n", sym.name, describe(conf, sym.val))
+			}
+			break Switch
+		}
+		name := p.need(scan.Identifier).Text
+		val := syms[name]
+		if val == nil {
+			p.errorf("%q not defined", name)
+		}
+		p.Printf("%s\t%s\n", name, describe(conf, val))
+	case "width":
+		if p.peek().Type == scan.EOF {
+			p.Printf("%d\n", conf.OutputWidth())
+			break Switch
+		}
+		width := 0
+		if !p.isDefault() {
+			width = p.nextDecimalNumber()
+		}
+		if width != 0 && width < minOutputWidth {
+			p.errorf("illegal width %d", width)
+		}
+		conf.SetOutputWidth(width)
 	default:
 		p.errorf(")%s: not recognized", text)
 	}
@@ -312,6 +699,133 @@ Switch:
 	p.need(scan.EOF)
 }
 
+// describe returns a short description of the value for )vars: the type and
+// shape of the value, plus the value itself if it is a scalar. Aggregates are
+// not printed, as they could be very large.
+func describe(conf *config.Config, val value.Value) string {
+	switch val.(type) {
+	case value.Vector, value.Range, *value.Matrix:
+		return typeAndShape(val)
+	}
+	return typeName(val) + " " + val.Sprint(conf)
+}
+
+// typeAndShape returns the type of the value and, for an aggregate,
+// its shape.
+func typeAndShape(val value.Value) string {
+	switch val := val.(type) {
+	case value.Vector:
+		return fmt.Sprintf("%s vector %d", elemType(val), len(val))
+	case value.Range:
+		return fmt.Sprintf("int vector %d", val.Len())
+	case *value.Matrix:
+		shape := fmt.Sprint(val.Shape())
+		return fmt.Sprintf("%s matrix %s", elemType(val.Data()), shape[1:len(shape)-1])
+	}
+	return typeName(val)
+}
+
+// elemType returns the name of the type of the elements of the vector,
+// or "mixed" if they are not all the same.
+func elemType(v value.Vector) string {
+	if len(v) == 0 {
+		return "empty"
+	}
+	name := typeName(v[0])
+	for _, elem := range v[1:] {
+		if typeName(elem) != name {
+			return "mixed"
+		}
+	}
+	return name
+}
+
+// typeName returns the user-visible name of the type of a scalar value.
+func typeName(val value.Value) string {
+	switch val.(type) {
+	case value.Int:
+		return "int"
+	case value.Char:
+		return "char"
+	case value.BigInt:
+		return "big int"
+	case value.BigRat:
+		return "rational"
+	case value.BigFloat:
+		return "float"
+	case value.Complex:
+		return "complex"
+	case value.Time:
+		return "time"
+	case value.Box:
+		return "box"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// isDefault reports whether the next token is the identifier "default",
+// consuming it if so.
+func (p *Parser) isDefault() bool {
+	if tok := p.peek(); tok.Type == scan.Identifier && tok.Text == "default" {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// notation returns the argument to ) format that sets the notation
+// used for floating-point values.
+func notation(conf *config.Config) string {
+	low, high, ok := conf.Notation()
+	switch {
+	case !ok:
+		return "auto"
+	case low == high:
+		return "sci"
+	case low == math.MinInt32 && high == math.MaxInt32:
+		return "fixed"
+	}
+	return fmt.Sprintf("auto %d %d", low, high)
+}
+
+// onOff returns "on" or "off" according to b.
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// needOnOff returns the setting given by the next word, which must be
+// "on" or "off". The command name is used in the error message.
+func (p *Parser) needOnOff(cmd string) bool {
+	switch arg := p.need(scan.Identifier).Text; arg {
+	case "on":
+		return true
+	case "off":
+		return false
+	default:
+		p.errorf("%s: expected on or off, got %q", cmd, arg)
+	}
+	panic("not reached")
+}
+
+// formatType returns the type name, one of config.FormatTypes, if one
+// is next in the input, or the empty string if not.
+func (p *Parser) formatType() string {
+	tok := p.peek()
+	if tok.Type != scan.Identifier {
+		return ""
+	}
+	for _, typ := range config.FormatTypes {
+		if tok.Text == typ {
+			p.next()
+			return typ
+		}
+	}
+	return ""
+}
+
 // getString returns the value of the string that must be next in the input.
 func (p *Parser) getString() string {
 	return value.ParseString(p.need(scan.String).Text)
@@ -321,6 +835,17 @@ var runDepth = 0
 
 // runFromFile executes the contents of the named file.
 func (p *Parser) runFromFile(context value.Context, name string) {
+	fd, err := open(name)
+	if err != nil {
+		p.errorf("%s", err)
+	}
+	defer fd.Close()
+	p.runFromReader(context, name, fd)
+}
+
+// runFromReader executes the ivy source text read from r.
+// The name is used in error messages.
+func (p *Parser) runFromReader(context value.Context, name string, r io.Reader) {
 	runDepth++
 	if runDepth > 10 {
 		p.errorf("get %q nested too deep", name)
@@ -332,16 +857,12 @@ func (p *Parser) runFromFile(context value.Context, name string) {
 			return
 		}
 		if err, ok := err.(value.Error); ok {
-			fmt.Fprintf(p.context.Config().ErrOutput(), "%s%s\n", p.Loc(), err)
+			fmt.Fprintf(p.context.Config().ErrOutput(), "%s\n", p.ErrorString(err))
 			return
 		}
 		panic(err)
 	}()
-	fd, err := os.Open(name)
-	if err != nil {
-		p.errorf("%s", err)
-	}
-	scanner := scan.New(context, name, bufio.NewReader(fd))
+	scanner := scan.New(context, name, bufio.NewReader(r))
 	parser := NewParser(name, scanner, p.context)
 	out := p.context.Config().Output()
 	for {
@@ -362,6 +883,26 @@ func (p *Parser) runFromFile(context value.Context, name string) {
 	}
 }
 
+// getTimeout bounds the time spent fetching a file with )get from a URL.
+const getTimeout = 30 * time.Second
+
+// open returns a reader for the named file, which may be an http or https URL.
+func open(name string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(name, "http://") && !strings.HasPrefix(name, "https://") {
+		return os.Open(name)
+	}
+	client := &http.Client{Timeout: getTimeout}
+	resp, err := client.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", name, resp.Status)
+	}
+	return resp.Body, nil
+}
+
 func exists(file string) bool {
 	info, err := os.Stat(file)
 	return err == nil && !info.IsDir()
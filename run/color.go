@@ -0,0 +1,58 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"io"
+	"os"
+
+	"robpike.io/ivy/config"
+	"robpike.io/ivy/value"
+)
+
+// ANSI escape sequences for the colors used in output.
+const (
+	colorReset  = "\x1b[0m"
+	colorNumber = "\x1b[36m" // Cyan.
+	colorString = "\x1b[32m" // Green.
+	colorError  = "\x1b[31m" // Red.
+)
+
+// colorize returns s wrapped in the escape sequences for the color,
+// if coloring is enabled and w is a terminal. Otherwise it returns s.
+func colorize(conf *config.Config, w io.Writer, color, s string) string {
+	if !conf.Color() || s == "" || !isTerminal(w) {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// valueColor returns the color in which to print v: text is printed
+// in one color and everything else, which is mostly numbers, in another.
+func valueColor(v value.Value) string {
+	switch v := v.(type) {
+	case value.Char:
+		return colorString
+	case value.Vector:
+		if v.AllChars() {
+			return colorString
+		}
+	case *value.Matrix:
+		if v.Data().AllChars() {
+			return colorString
+		}
+	}
+	return colorNumber
+}
@@ -39,6 +39,7 @@ func IvyEval(context value.Context, str string) value.Value {
 func Run(p *parse.Parser, context value.Context, interactive bool) (success bool) {
 	conf := context.Config()
 	writer := conf.Output()
+	p.SetInteractive(interactive)
 	defer func() {
 		if conf.Debug("panic") {
 			return
@@ -48,7 +49,8 @@ func Run(p *parse.Parser, context value.Context, interactive bool) (success bool
 			return
 		}
 		if err, ok := err.(value.Error); ok {
-			fmt.Fprintf(conf.ErrOutput(), "%s%s\n", p.Loc(), err)
+			errOutput := conf.ErrOutput()
+			fmt.Fprintf(errOutput, "%s\n", colorize(conf, errOutput, colorError, p.ErrorString(err)))
 			if interactive {
 				fmt.Fprintln(writer)
 			}
@@ -59,18 +61,12 @@ func Run(p *parse.Parser, context value.Context, interactive bool) (success bool
 	}()
 	for {
 		if interactive {
-			fmt.Fprint(writer, conf.Prompt())
+			fmt.Fprint(writer, prompt(conf, p))
 		}
 		exprs, ok := p.Line()
 		var values []value.Value
 		if exprs != nil {
-			if interactive {
-				start := time.Now()
-				values = context.Eval(exprs)
-				conf.SetCPUTime(time.Since(start))
-			} else {
-				values = context.Eval(exprs)
-			}
+			values = evaluate(context, exprs, interactive)
 		}
 		if printValues(conf, writer, values) {
 			context.Assign("_", values[len(values)-1])
@@ -87,6 +83,48 @@ func Run(p *parse.Parser, context value.Context, interactive bool) (success bool
 	}
 }
 
+// prompt returns the interactive prompt, expanding %n to the number of
+// the next input line, %b to the input base and %% to a percent sign.
+// Input base 0, the default, reads decimal, so it shows as 10.
+func prompt(conf *config.Config, p *parse.Parser) string {
+	base := conf.InputBase()
+	if base == 0 {
+		base = 10
+	}
+	r := strings.NewReplacer(
+		"%%", "%",
+		"%n", fmt.Sprint(p.InputLine()),
+		"%b", fmt.Sprint(base),
+	)
+	return r.Replace(conf.Prompt())
+}
+
+// Interrupt handles an interrupt, such as from typing control-C. It
+// stops the evaluation in progress; if there is none, it prints the
+// prompt afresh on a new line, to show the interrupt was seen.
+func Interrupt(p *parse.Parser, conf *config.Config) {
+	if !conf.Interrupt() {
+		fmt.Fprint(conf.Output(), "\n"+prompt(conf, p))
+	}
+}
+
+// evaluate evaluates the expressions of one line, subject to the
+// timeout. An interactive evaluation records its CPU time.
+func evaluate(context value.Context, exprs []value.Expr, interactive bool) []value.Value {
+	conf := context.Config()
+	// Forget any interrupt left over from the previous evaluation.
+	conf.Interrupted()
+	defer conf.StartEvaluation()()
+	defer conf.StartTimeout()()
+	if !interactive {
+		return context.Eval(exprs)
+	}
+	start := time.Now()
+	values := context.Eval(exprs)
+	conf.SetCPUTime(time.Since(start))
+	return values
+}
+
 // eval runs until EOF or error. It prints every value but the last, and returns the last.
 // By last we mean the last expression of the last evaluation.
 // (Expressions are separated by ; in the input.)
@@ -135,11 +173,11 @@ func printValues(conf *config.Config, writer io.Writer, values []value.Value) bo
 		if _, ok := v.(parse.Assignment); ok {
 			continue
 		}
-		s := v.Sprint(conf)
+		s := value.PrintString(conf, v)
 		if printed && len(s) > 0 && s[len(s)-1] != '\n' {
 			fmt.Fprint(writer, " ")
 		}
-		fmt.Fprint(writer, s)
+		fmt.Fprint(writer, colorize(conf, writer, valueColor(v), s))
 		printed = true
 	}
 	if printed {
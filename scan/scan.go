@@ -129,11 +129,13 @@ func (l *Scanner) peek() rune {
 
 // peek2 returns the next two runes ahead, but does not consume anything.
 func (l *Scanner) peek2() (rune, rune) {
-	pos := l.pos
+	// Reading may load another line, which moves the
+	// pending input, so remember the position relative to it.
+	offset := l.pos - l.start
 	width := l.width
 	r1 := l.next()
 	r2 := l.next()
-	l.pos = pos
+	l.pos = l.start + offset
 	l.width = width
 	return r1, r2
 }
@@ -179,6 +181,59 @@ func (l *Scanner) acceptRun(valid string) {
 	l.backup()
 }
 
+// acceptDigits accepts a run of digits, which may be separated by single
+// underscores for readability, as in 1_000_000. It reports false, having
+// consumed the underscore, if an underscore is leading, trailing or doubled.
+func (l *Scanner) acceptDigits(digits string) bool {
+	for {
+		l.acceptRun(digits)
+		if l.peek() != '_' {
+			return true
+		}
+		leading := l.pos == 0 || !strings.ContainsRune(digits, rune(l.input[l.pos-1]))
+		l.next()
+		if leading || !strings.ContainsRune(digits, l.peek()) {
+			return false
+		}
+	}
+}
+
+// badNumber reports a malformed number, which has been scanned up to the
+// current position. The message shows the rest of the literal too.
+func (l *Scanner) badNumber() stateFn {
+	underscore := strings.HasSuffix(l.input[l.start:l.pos], "_")
+	for isAlphaNumeric(l.peek()) || l.peek() == '.' {
+		l.next()
+	}
+	text := l.input[l.start:l.pos]
+	if underscore {
+		return l.errorf("bad number syntax: misplaced underscore in %s", text)
+	}
+	return l.errorf("bad number syntax: %s", text)
+}
+
+// acceptGroups consumes groups of digits introduced by the digit
+// grouping separator, if one is configured, so numbers printed with
+// grouping can be read back. Each group must have exactly the
+// configured number of digits; otherwise the separator is left alone.
+func (l *Scanner) acceptGroups(digits string) {
+	sep, size := l.context.Config().Grouping()
+	if sep == "" {
+		return
+	}
+	for strings.HasPrefix(l.input[l.pos:], sep) {
+		rest := l.input[l.pos+len(sep):]
+		n := 0
+		for n < len(rest) && strings.IndexByte(digits, rest[n]) >= 0 {
+			n++
+		}
+		if n != size {
+			return
+		}
+		l.pos += len(sep) + n
+	}
+}
+
 // errorf returns an error token, replaces the input line with a
 // newline (so the next token will be a newline, skipping the
 // rest of the current line), and continues to scan.
@@ -243,6 +298,28 @@ func lexComment(l *Scanner) stateFn {
 	return lexSpace
 }
 
+// lexBlockComment scans a block comment, which may span lines and
+// may be nested. The opening /* has been consumed. The comment is
+// skipped like a space, but the lines within it are counted.
+func lexBlockComment(l *Scanner) stateFn {
+	for depth := 1; depth > 0; {
+		switch r := l.next(); {
+		case r == eof:
+			return l.errorf("unterminated block comment")
+		case r == '\n':
+			l.line++
+		case r == '/' && l.peek() == '*':
+			l.next()
+			depth++
+		case r == '*' && l.peek() == '/':
+			l.next()
+			depth--
+		}
+	}
+	l.ignore()
+	return lexAny
+}
+
 // lexAny scans non-space items.
 func lexAny(l *Scanner) stateFn {
 	switch r := l.next(); {
@@ -256,6 +333,9 @@ func lexAny(l *Scanner) stateFn {
 		return lexAny
 	case r == '#':
 		return lexComment
+	case r == '/' && l.peek() == '*':
+		l.next()
+		return lexBlockComment
 	case isSpace(r):
 		return lexSpace
 	case r == '"':
@@ -334,6 +414,10 @@ func lexIdentifier(l *Scanner) stateFn {
 Loop:
 	for {
 		switch r := l.next(); {
+		case r == 'j' && l.isComplex():
+			// A complex number whose real part is spelled with letters.
+			l.backup()
+			return lexImaginary
 		case isAlphaNumeric(r):
 			// absorb.
 		default:
@@ -361,6 +445,20 @@ Loop:
 	return lexAny
 }
 
+// isComplex reports whether the identifier being scanned, up to the
+// j just consumed, is the real part of a complex number. That is
+// possible only in input bases above 10, in which a number may begin
+// with a letter, but below 20, in which j is a numeral.
+func (l *Scanner) isComplex() bool {
+	base := l.context.Config().InputBase()
+	word := l.input[l.start : l.pos-1]
+	if base <= 10 || base >= 20 || !isAllDigits(word, base) {
+		return false
+	}
+	r := l.peek()
+	return r == '-' || r == '.' || l.isNumeral(r)
+}
+
 // lexOperator completes scanning an operator. We have already accepted the + or
 // whatever; there may be a reduction or inner or outer product.
 func lexOperator(l *Scanner) stateFn {
@@ -369,11 +467,13 @@ func lexOperator(l *Scanner) stateFn {
 	if word == "o" || value.BinaryOps[word] != nil || l.context.UserDefined(word, true) {
 		switch l.peek() {
 		case '/':
-			// Reduction.
+			// Reduction; a following % means along the first axis.
 			l.next()
+			l.accept("%")
 		case '\\':
-			// Scan.
+			// Scan; a following % means along the first axis.
 			l.next()
+			l.accept("%")
 		case '.':
 			// Inner or outer product?
 			l.next()               // Accept the '.'.
@@ -456,6 +556,7 @@ func lexNumber(l *Scanner) stateFn {
 		// Might be a scan or reduction.
 		if r == '/' || r == '\\' {
 			l.next()
+			l.accept("%")
 			l.emit(Operator)
 			return lexAny
 		}
@@ -465,9 +566,12 @@ func lexNumber(l *Scanner) stateFn {
 		}
 	}
 	if !l.scanNumber(true) {
-		return l.errorf("bad number syntax: %s", l.input[l.start:l.pos])
+		return l.badNumber()
 	}
 	r := l.peek()
+	if r == 'j' {
+		return lexImaginary
+	}
 	if r != '/' {
 		l.emit(Number)
 		return lexAny
@@ -484,38 +588,100 @@ func lexNumber(l *Scanner) stateFn {
 		return lexAny
 	}
 	if !l.scanNumber(false) {
-		return l.errorf("bad number syntax: %s", l.input[l.start:l.pos])
+		return l.badNumber()
 	}
 	if l.peek() == '.' {
 		return l.errorf("bad number syntax: %s", l.input[l.start:l.pos+1])
 	}
+	if l.peek() == 'j' {
+		return lexImaginary
+	}
 	l.emit(Rational)
 	return lexAny
 }
 
+// lexImaginary scans the imaginary part of a complex number such as 3j4,
+// which may be signed or rational. The real part has been scanned and
+// the next rune is the j.
+func lexImaginary(l *Scanner) stateFn {
+	l.accept("j")
+	l.accept("-")
+	if r := l.peek(); r != '.' && !l.isNumeral(r) {
+		return l.badNumber()
+	}
+	if !l.scanNumber(true) {
+		return l.badNumber()
+	}
+	if l.accept("/") {
+		if !l.scanNumber(false) {
+			return l.badNumber()
+		}
+	}
+	if l.peek() == 'j' {
+		return l.errorf("bad number syntax: %s", l.input[l.start:l.pos+1])
+	}
+	l.emit(Number)
+	return lexAny
+}
+
 func (l *Scanner) scanNumber(followingSlashOK bool) bool {
 	base := l.context.Config().InputBase()
 	digits := digitsForBase(base)
-	// If base 0, acccept octal for 0 or hex for 0x or 0X.
-	if base == 0 {
-		if l.accept("0") && l.accept("xX") {
-			digits = digitsForBase(16)
+	// If base 0, acccept octal for 0 or 0o, hex for 0x or 0X, and binary for 0b.
+	// Numbers with these prefixes may have a binary exponent, as in 0x1.8p3.
+	prefixed := false
+	if base == 0 && l.accept("0") {
+		// Look at the second rune only if the first might start a prefix,
+		// so as not to read past the end of the line.
+		var r1, r2 rune
+		if r1 = l.peek(); strings.ContainsRune("bBoO", r1) {
+			r1, r2 = l.peek2()
+		}
+		switch {
+		case r1 == 'x' || r1 == 'X':
+			l.next()
+			digits, prefixed = digitsForBase(16), true
+		case (r1 == 'b' || r1 == 'B') && (r2 == '0' || r2 == '1'):
+			// The digit is required: 0b would otherwise be an error.
+			l.next()
+			digits, prefixed = "01", true
+		case (r1 == 'o' || r1 == 'O') && '0' <= r2 && r2 <= '7':
+			// The digit is required: 0o. is an outer product.
+			l.next()
+			digits, prefixed = "01234567", true
 		}
 		// Otherwise leave it decimal (0); strconv.ParseInt will take care of it.
 		// We can't set it to 8 in case it's a leading-0 float like 0.69 or 09e4.
 	}
-	l.acceptRun(digits)
-	if l.accept(".") {
-		l.acceptRun(digits)
+	if !l.acceptDigits(digits) {
+		return false
+	}
+	l.acceptGroups(digits)
+	if l.accept(".") && !l.acceptDigits(digits) {
+		return false
 	}
-	if l.accept("eE") {
+	if prefixed {
+		if l.accept("pP") {
+			l.accept("+-")
+			if !l.acceptDigits("0123456789") {
+				return false
+			}
+		}
+	} else if l.accept("eE") {
 		l.accept("+-")
-		l.acceptRun("0123456789")
+		if !l.acceptDigits("0123456789") {
+			return false
+		}
 	}
 	r := l.peek()
 	if followingSlashOK && r == '/' {
 		return true
 	}
+	// A j introduces the imaginary part of a complex number (3j4).
+	// In bases above 19, it is a digit and has been consumed already.
+	if r == 'j' {
+		return true
+	}
 	// Next thing mustn't be alphanumeric except possibly an o for outer product (3o.+2).
 	if r != 'o' && isAlphaNumeric(r) {
 		l.next()
@@ -577,13 +743,15 @@ Loop:
 	return lexAny
 }
 
-// lexRawQuote scans a raw quoted string.
+// lexRawQuote scans a raw quoted string, which may span lines.
 func lexRawQuote(l *Scanner) stateFn {
 Loop:
 	for {
 		switch l.next() {
 		case eof:
 			return l.errorf("unterminated raw quoted string")
+		case '\n':
+			l.line++
 		case '`':
 			break Loop
 		}
@@ -653,14 +821,18 @@ func (l *Scanner) isNumeral(r rune) bool {
 	return false
 }
 
-// isAllDigits reports whether s consists of digits in the specified base.
+// isAllDigits reports whether s consists of digits in the specified base,
+// possibly separated by single underscores.
 func isAllDigits(s string, base int) bool {
 	top := 'a' + rune(base-10) - 1
 	TOP := 'A' + rune(base-10) - 1
-	for _, c := range s {
+	for i, c := range s {
 		if '0' <= c && c <= '9' {
 			continue
 		}
+		if c == '_' && i > 0 && s[i-1] != '_' && i < len(s)-1 {
+			continue
+		}
 		if 'a' <= c && c <= top {
 			continue
 		}
@@ -676,7 +848,7 @@ func isAllDigits(s string, base int) bool {
 // if it is a two-character operator.
 func (l *Scanner) isOperator(r rune) bool {
 	switch r {
-	case '?', '+', '-', '/', '%', '&', '|', '^', ',':
+	case '?', '+', '-', '/', '\\', '%', '&', '|', '^', ',':
 		// No follow-on possible.
 	case '!':
 		if l.peek() == '=' {
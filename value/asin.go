@@ -36,6 +36,9 @@ func floatAsin(c Context, x *big.Float) *big.Float {
 	z.Mul(x, x)
 	z.Sub(floatOne, z)
 	z = floatSqrt(c, z)
+	if z == floatNaN {
+		return z
+	}
 	z.Quo(x, z)
 	return floatAtan(c, z)
 }
@@ -43,9 +46,13 @@ func floatAsin(c Context, x *big.Float) *big.Float {
 // floatAcos computes acos(x) as π/2 - asin(x).
 func floatAcos(c Context, x *big.Float) *big.Float {
 	// acos(x) = π/2 - asin(x)
+	y := floatAsin(c, x)
+	if y == floatNaN {
+		return y
+	}
 	z := newFloat(c).Set(floatPi)
 	z.Quo(z, newFloat(c).SetInt64(2))
-	return z.Sub(z, floatAsin(c, x))
+	return z.Sub(z, y)
 }
 
 // floatAtan computes atan(x) using a Taylor series. There are two series,
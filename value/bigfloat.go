@@ -7,6 +7,8 @@ package value
 import (
 	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 
 	"robpike.io/ivy/config"
 )
@@ -34,6 +36,14 @@ func (f BigFloat) String() string {
 }
 
 func (f BigFloat) Sprint(conf *config.Config) string {
+	switch {
+	case f.Float == floatNaN:
+		return "NaN"
+	case f.IsInf() && f.Sign() < 0:
+		return "-Inf"
+	case f.IsInf():
+		return "Inf"
+	}
 	var mant big.Float
 	exp := f.Float.MantExp(&mant)
 	positive := 1
@@ -42,12 +52,14 @@ func (f BigFloat) Sprint(conf *config.Config) string {
 		exp = -exp
 	}
 	verb, prec := byte('g'), 12
-	format := conf.Format()
+	format := conf.FormatFor("float")
 	if format != "" {
-		v, p, ok := conf.FloatFormat()
+		v, p, ok := conf.FloatFormatFor("float")
 		if ok {
 			verb, prec = v, p
 		}
+	} else if prefix := floatBasePrefix[conf.OutputBase()]; prefix != "" {
+		return f.powerOfTwoString(conf.OutputBase(), prefix)
 	}
 	// Printing huge floats can be very slow using
 	// big.Float's native methods; see issue #11068.
@@ -119,9 +131,98 @@ func (f BigFloat) Sprint(conf *config.Config) string {
 			return fmt.Sprintf("%s%s%c%c%d", sign, digits, eChar, "-+"[positive], i64exp)
 		}
 	}
+	if low, high, ok := conf.Notation(); ok && (verb == 'g' || verb == 'G') {
+		return f.notationString(verb, prec, low, high)
+	}
 	return f.Float.Text(verb, prec)
 }
 
+// notationString formats f like the %g verb with the given precision,
+// but chooses fixed notation only if the decimal exponent is at least
+// low and less than high. As with %g, trailing zeros are removed.
+func (f BigFloat) notationString(verb byte, prec, low, high int) string {
+	if f.IsInf() {
+		return f.Float.Text(verb, prec)
+	}
+	if prec == 0 {
+		prec = 1
+	}
+	eChar := "e"
+	if verb == 'G' {
+		eChar = "E"
+	}
+	s := f.Float.Text('e', prec-1)
+	i := strings.LastIndexAny(s, "e")
+	exp, _ := strconv.Atoi(s[i+1:])
+	s = s[:i]
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	digits := strings.TrimRight(strings.Replace(s, ".", "", 1), "0")
+	if digits == "" {
+		digits, exp = "0", 0
+	}
+	switch {
+	case exp < low || exp >= high:
+		if len(digits) > 1 {
+			digits = digits[:1] + "." + digits[1:]
+		}
+		return fmt.Sprintf("%s%s%s%+03d", sign, digits, eChar, exp)
+	case exp < 0:
+		return sign + "0." + strings.Repeat("0", -exp-1) + digits
+	case len(digits) <= exp+1:
+		return sign + digits + strings.Repeat("0", exp+1-len(digits))
+	}
+	return sign + digits[:exp+1] + "." + digits[exp+1:]
+}
+
+// floatBasePrefix holds the number prefixes for the output bases in which
+// floats are printed exactly, with a binary exponent.
+var floatBasePrefix = map[int]string{
+	2:  "0b",
+	8:  "0o",
+	16: "0x",
+}
+
+// powerOfTwoString formats f exactly in the given base, which must be
+// 2, 8, or 16, in the style of C's %a format: a prefixed mantissa with
+// a single leading 1 digit, followed by p and the decimal exponent of 2.
+// For example, 1.5 is 0x1.8p0 in base 16. The result can be read back
+// with the default input base.
+func (f BigFloat) powerOfTwoString(base int, prefix string) string {
+	if f.IsInf() {
+		return f.Float.Text('g', 12)
+	}
+	sign := ""
+	if f.Sign() < 0 {
+		sign = "-"
+	}
+	if f.Sign() == 0 {
+		return sign + prefix + "0p0"
+	}
+	// f = mant × 2**exp, with 0.5 <= mant < 1.
+	var mant big.Float
+	exp := f.Float.MantExp(&mant)
+	mant.Abs(&mant)
+	// Make the mantissa an integer whose top bit is the leading 1,
+	// with the fraction bits padded to a whole number of digits.
+	bits := int(mant.MinPrec())
+	digitBits := 0
+	for b := base; b > 1; b >>= 1 {
+		digitBits++
+	}
+	fracBits := bits - 1
+	pad := (digitBits - fracBits%digitBits) % digitBits
+	m, _ := mant.SetMantExp(&mant, bits+pad).Int(nil)
+	digits := m.Text(base)
+	s := sign + prefix + digits[:1]
+	if len(digits) > 1 {
+		s += "." + digits[1:]
+	}
+	return s + "p" + strconv.Itoa(exp-1)
+}
+
 func (f BigFloat) ProgString() string {
 	// There is no such thing as a float literal in program listings.
 	panic("float.ProgString - cannot happen")
@@ -139,6 +240,8 @@ func (f BigFloat) toType(conf *config.Config, which valueType) Value {
 	switch which {
 	case bigFloatType:
 		return f
+	case complexType:
+		return Complex{f, zero}
 	case vectorType:
 		return NewVector([]Value{f})
 	case matrixType:
@@ -150,6 +253,9 @@ func (f BigFloat) toType(conf *config.Config, which valueType) Value {
 
 // shrink shrinks, if possible, a BigFloat down to an integer type.
 func (f BigFloat) shrink() Value {
+	if f.Float == floatNaN {
+		return f
+	}
 	exp := f.MantExp(nil)
 	if exp <= 100 && f.IsInt() { // Huge integers are not pretty. (Exp here is power of two.)
 		i, _ := f.Int(nil) // Result guaranteed exact.
@@ -42,15 +42,19 @@ func (i BigInt) String() string {
 }
 
 func (i BigInt) Sprint(conf *config.Config) string {
+	return i.sprint(conf, conf.FormatFor("int"))
+}
+
+// sprint is Sprint using the given format rather than the configured one.
+func (i BigInt) sprint(conf *config.Config, format string) string {
 	bitLen := i.BitLen()
-	format := conf.Format()
 	var maxBits = (uint64(conf.MaxDigits()) * 33222) / 10000 // log 10 / log 2 is 3.32192809489
 	if uint64(bitLen) > maxBits && maxBits != 0 {
 		// Print in floating point.
 		return BigFloat{newF(conf).SetInt(i.Int)}.Sprint(conf)
 	}
 	if format != "" {
-		verb, prec, ok := conf.FloatFormat()
+		verb, prec, ok := conf.FloatFormatFor("int")
 		if ok {
 			return i.floatString(verb, prec)
 		}
@@ -58,11 +62,11 @@ func (i BigInt) Sprint(conf *config.Config) string {
 	}
 	// Is this from a rational and we could use an int?
 	if i.BitLen() < intBits {
-		return Int(i.Int64()).Sprint(conf)
+		return Int(i.Int64()).sprint(conf, format)
 	}
 	switch conf.OutputBase() {
 	case 0, 10:
-		return fmt.Sprintf("%d", i.Int)
+		return group(conf, fmt.Sprintf("%d", i.Int))
 	case 2:
 		return fmt.Sprintf("%b", i.Int)
 	case 8:
@@ -154,6 +158,8 @@ func (i BigInt) toType(conf *config.Config, which valueType) Value {
 	case bigFloatType:
 		f := new(big.Float).SetPrec(conf.FloatPrec()).SetInt(i.Int)
 		return BigFloat{f}
+	case complexType:
+		return Complex{i, zero}
 	case vectorType:
 		return NewVector([]Value{i})
 	case matrixType:
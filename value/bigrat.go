@@ -22,7 +22,7 @@ type BigRat struct {
 func setBigRatFromFloatString(_ *config.Config, s string) (br BigRat, err error) {
 	// Be safe: Verify that it is floating-point, because otherwise
 	// we need to honor ibase.
-	if !strings.ContainsAny(s, ".eE") {
+	if !strings.ContainsAny(s, ".eEpP") {
 		// Most likely a number like "08".
 		Errorf("bad number syntax: %s", s)
 	}
@@ -43,17 +43,21 @@ func (r BigRat) Rank() int {
 }
 
 func (r BigRat) Sprint(conf *config.Config) string {
-	format := conf.Format()
+	if conf.RationalDecimal() {
+		return BigFloat{newF(conf).SetRat(r.Rat)}.Sprint(conf)
+	}
+	format := conf.FormatFor("rat")
 	if format != "" {
-		verb, prec, ok := conf.FloatFormat()
+		verb, prec, ok := conf.FloatFormatFor("rat")
 		if ok {
 			return r.floatString(verb, prec)
 		}
-		return fmt.Sprintf(conf.RatFormat(), r.Num(), r.Denom())
+		return fmt.Sprintf(conf.RatFormatFor("rat"), r.Num(), r.Denom())
 	}
+	// Any format for integers does not apply to the parts of a rational.
 	num := BigInt{r.Num()}
 	den := BigInt{r.Denom()}
-	return fmt.Sprintf("%s/%s", num.Sprint(conf), den.Sprint(conf))
+	return fmt.Sprintf("%s/%s", num.sprint(conf, ""), den.sprint(conf, ""))
 }
 
 func (r BigRat) ProgString() string {
@@ -181,6 +185,8 @@ func (r BigRat) toType(conf *config.Config, which valueType) Value {
 	case bigFloatType:
 		f := new(big.Float).SetPrec(conf.FloatPrec()).SetRat(r.Rat)
 		return BigFloat{f}
+	case complexType:
+		return Complex{r, zero}
 	case vectorType:
 		return NewVector([]Value{r})
 	case matrixType:
@@ -197,3 +203,18 @@ func (r BigRat) shrink() Value {
 	}
 	return BigInt{r.Num()}.shrink()
 }
+
+// roundRat returns r rounded to the nearest integer. Ties round to
+// the even neighbor, so 1/2 and 5/2 round to 0 and 2.
+func roundRat(r *big.Rat) Value {
+	q, m := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+	switch m.Lsh(m, 1).Cmp(r.Denom()) {
+	case 1:
+		q.Add(q, bigOne.Int)
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, bigOne.Int)
+		}
+	}
+	return BigInt{q}.shrink()
+}
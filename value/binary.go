@@ -62,6 +62,10 @@ func shiftCount(x Value) uint {
 		if _, ok := reduced.(Int); ok {
 			return shiftCount(reduced)
 		}
+		if count.Sign() < 0 {
+			Errorf("illegal shift count %s", count)
+		}
+		Errorf("shift count too large")
 	}
 	Errorf("illegal shift count type")
 	panic("not reached")
@@ -127,6 +131,53 @@ func bigIntExpOp(c Context) func(i, j, k *big.Int) *big.Int {
 	}
 }
 
+// bigIntGCD is the "op" for gcd on *big.Int. The result is never negative,
+// and the GCD of zero and x is abs(x).
+func bigIntGCD(i, j, k *big.Int) *big.Int {
+	return i.GCD(nil, nil, j, k)
+}
+
+// bigIntLCM is the "op" for lcm on *big.Int. The result is never negative,
+// and the LCM of zero and anything is zero.
+func bigIntLCM(i, j, k *big.Int) *big.Int {
+	if j.Sign() == 0 || k.Sign() == 0 {
+		return i.SetInt64(0)
+	}
+	gcd := new(big.Int).GCD(nil, nil, j, k)
+	i.Quo(j, gcd)
+	i.Mul(i, k)
+	return i.Abs(i)
+}
+
+// lsh is the implementation of <<. Shifting a negative value
+// preserves its sign, as for Go's big.Int.
+func lsh(c Context, u, v Value) Value {
+	i, j := u.(BigInt), v.(BigInt)
+	n := shiftCount(j)
+	if i.Sign() != 0 {
+		mustFit(c.Config(), int64(i.BitLen())+int64(n))
+	}
+	z := bigInt64(0)
+	z.Lsh(i.Int, n)
+	return z.shrink()
+}
+
+// rsh is the implementation of >>. It is an arithmetic shift, so
+// negative values round towards negative infinity.
+func rsh(c Context, u, v Value) Value {
+	i, j := u.(BigInt), v.(BigInt)
+	if j.Sign() > 0 && j.BitLen() > 62 {
+		// Every bit is shifted out.
+		if i.Sign() < 0 {
+			return minusOne
+		}
+		return zero
+	}
+	z := bigInt64(0)
+	z.Rsh(i.Int, shiftCount(j))
+	return z.shrink()
+}
+
 // toInt turns the boolean into an Int 0 or 1.
 func toInt(t bool) Value {
 	if t {
@@ -135,6 +186,94 @@ func toInt(t bool) Value {
 	return zero
 }
 
+// roundTo returns a function that rounds v to a multiple of u, using
+// the unary operator op to round the quotient v/u to an integer.
+// Rational operands give exact results.
+func roundTo(op string) binaryFn {
+	return func(c Context, u, v Value) Value {
+		if !toBool(u) {
+			Errorf("%s: multiple must be non-zero", op)
+		}
+		q := c.EvalUnary(op, c.EvalBinary(v, "/", u))
+		return c.EvalBinary(u, "*", q)
+	}
+}
+
+// minMax implements min and max. Scalars are compared exactly and the
+// result is one of them unchanged, so the max of a float and an integer
+// too big to be represented in the float's precision is the integer.
+func minMax(c Context, op string, u, v Value) Value {
+	if whichType(u) >= vectorType || whichType(v) >= vectorType {
+		which := atLeastVectorType(whichType(u), whichType(v))
+		u = u.toType(c.Config(), which)
+		v = v.toType(c.Config(), which)
+		if which == vectorType {
+			return binaryVectorOp(c, u, op, v)
+		}
+		return binaryMatrixOp(c, u, op, v)
+	}
+	if r, ok := specialBinary(op, u, v); ok {
+		return r
+	}
+	cmp := exactCmp(c, op, u, v)
+	if op == "min" && cmp < 0 || op == "max" && cmp > 0 {
+		return u
+	}
+	return v
+}
+
+// exactCmp compares the scalars u and v. Unlike the usual conversion
+// for arithmetic, a float compared with an exact value is converted to
+// a rational, which loses nothing.
+func exactCmp(c Context, op string, u, v Value) int {
+	which := binaryArithType(whichType(u), whichType(v))
+	if which == bigFloatType && !isInf(u) && !isInf(v) {
+		ur, vr := floatRat(u), floatRat(v)
+		if ur != nil && vr != nil {
+			return ur.Cmp(vr)
+		}
+	}
+	conf := c.Config()
+	u = u.toType(conf, which)
+	v = v.toType(conf, which)
+	switch which {
+	case intType:
+		return compareInts(int64(u.(Int)), int64(v.(Int)))
+	case charType:
+		return compareInts(int64(u.(Char)), int64(v.(Char)))
+	case bigIntType:
+		return u.(BigInt).Cmp(v.(BigInt).Int)
+	case bigRatType:
+		return u.(BigRat).Cmp(v.(BigRat).Rat)
+	case bigFloatType:
+		return u.(BigFloat).Cmp(v.(BigFloat).Float)
+	}
+	Errorf("binary %s not implemented on type %s", op, which)
+	return 0
+}
+
+// compareInts returns -1, 0 or 1 according to whether a is less than,
+// equal to or greater than b.
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// floatRat returns the exact value of a number as a big.Rat, or nil
+// if it is not an integer, rational or finite float.
+func floatRat(v Value) *big.Rat {
+	if f, ok := v.(BigFloat); ok {
+		r, _ := f.Rat(nil)
+		return r
+	}
+	return exactRat(v)
+}
+
 // toBool turns the Value into a Go bool.
 func toBool(t Value) bool {
 	switch t := t.(type) {
@@ -148,6 +287,8 @@ func toBool(t Value) bool {
 		return t.Sign() != 0
 	case BigFloat:
 		return t.Sign() != 0
+	case Complex:
+		return toBool(t.real) || toBool(t.imag)
 	}
 	Errorf("cannot convert %T to bool", t)
 	panic("not reached")
@@ -173,7 +314,7 @@ func init() {
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
 				intType: func(c Context, u, v Value) Value {
-					return (u.(Int) + v.(Int)).maybeBig()
+					return (u.(Int) + v.(Int)).maybeBig(c, u.(Int), "+", v.(Int))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
 					mustFit(c.Config(), u.(BigInt).BitLen()+1)
@@ -186,6 +327,9 @@ func init() {
 				bigFloatType: func(c Context, u, v Value) Value {
 					return binaryBigFloatOp(c, u, (*big.Float).Add, v)
 				},
+				complexType: func(c Context, u, v Value) Value {
+					return u.(Complex).add(c, v.(Complex))
+				},
 			},
 		},
 
@@ -195,7 +339,7 @@ func init() {
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
 				intType: func(c Context, u, v Value) Value {
-					return (u.(Int) - v.(Int)).maybeBig()
+					return (u.(Int) - v.(Int)).maybeBig(c, u.(Int), "-", v.(Int))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
 					mustFit(c.Config(), u.(BigInt).BitLen()+1)
@@ -208,6 +352,9 @@ func init() {
 				bigFloatType: func(c Context, u, v Value) Value {
 					return binaryBigFloatOp(c, u, (*big.Float).Sub, v)
 				},
+				complexType: func(c Context, u, v Value) Value {
+					return u.(Complex).sub(c, v.(Complex))
+				},
 			},
 		},
 
@@ -217,7 +364,7 @@ func init() {
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
 				intType: func(c Context, u, v Value) Value {
-					return (u.(Int) * v.(Int)).maybeBig()
+					return (u.(Int) * v.(Int)).maybeBig(c, u.(Int), "*", v.(Int))
 				},
 				bigIntType: func(c Context, u, v Value) Value {
 					mustFit(c.Config(), u.(BigInt).BitLen()+v.(BigInt).BitLen())
@@ -229,6 +376,9 @@ func init() {
 				bigFloatType: func(c Context, u, v Value) Value {
 					return binaryBigFloatOp(c, u, (*big.Float).Mul, v)
 				},
+				complexType: func(c Context, u, v Value) Value {
+					return u.(Complex).mul(c, v.(Complex))
+				},
 			},
 		},
 
@@ -239,13 +389,19 @@ func init() {
 			fn: [numType]binaryFn{
 				bigRatType: func(c Context, u, v Value) Value {
 					if v.(BigRat).Sign() == 0 {
-						Errorf("division by zero")
+						return divideByZero(c, u)
 					}
 					return binaryBigRatOp(u, (*big.Rat).Quo, v) // True division.
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
+					if v.(BigFloat).Sign() == 0 {
+						return divideByZero(c, u)
+					}
 					return binaryBigFloatOp(c, u, (*big.Float).Quo, v)
 				},
+				complexType: func(c Context, u, v Value) Value {
+					return u.(Complex).quo(c, v.(Complex))
+				},
 			},
 		},
 
@@ -325,6 +481,42 @@ func init() {
 			},
 		},
 
+		{
+			name:        "gcd",
+			elementwise: true,
+			whichType:   divType, // Use BigInts to avoid the analysis here.
+			fn: [numType]binaryFn{
+				bigIntType: func(c Context, u, v Value) Value {
+					return binaryBigIntOp(u, bigIntGCD, v)
+				},
+			},
+		},
+
+		{
+			name:        "lcm",
+			elementwise: true,
+			whichType:   divType, // Use BigInts to avoid the analysis here.
+			fn: [numType]binaryFn{
+				bigIntType: func(c Context, u, v Value) Value {
+					return binaryBigIntOp(u, bigIntLCM, v)
+				},
+			},
+		},
+
+		{
+			name:      "powmod",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return powmod(c, u.(Vector), v.(Vector)).shrink()
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					m := v.(*Matrix)
+					return NewMatrix(m.shape, powmod(c, u.(*Matrix).data, m.data))
+				},
+			},
+		},
+
 		{
 			name:        "**",
 			elementwise: true,
@@ -336,7 +528,7 @@ func init() {
 						return one
 					case -1:
 						if u.(BigInt).Sign() == 0 {
-							Errorf("negative exponent of zero")
+							return zeroToNegativePower(c)
 						}
 						v = c.EvalUnary("abs", v).toType(c.Config(), bigIntType)
 						return c.EvalUnary("/", binaryBigIntOp(u, bigIntExpOp(c), v))
@@ -356,7 +548,7 @@ func init() {
 						return one
 					case -1:
 						if u.(BigRat).Sign() == 0 {
-							Errorf("negative exponent of zero")
+							return zeroToNegativePower(c)
 						}
 						positive = false
 						rexp = c.EvalUnary("-", v).toType(c.Config(), bigRatType).(BigRat)
@@ -380,6 +572,21 @@ func init() {
 					return z.shrink()
 				},
 				bigFloatType: func(c Context, u, v Value) Value { return power(c, u, v) },
+				complexType: func(c Context, u, v Value) Value {
+					return u.(Complex).pow(c, v.(Complex))
+				},
+			},
+		},
+
+		{
+			name:        "root",
+			elementwise: true,
+			whichType:   binaryArithType,
+			fn: [numType]binaryFn{
+				intType:      root,
+				bigIntType:   root,
+				bigRatType:   root,
+				bigFloatType: root,
 			},
 		},
 
@@ -400,22 +607,10 @@ func init() {
 			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					a := int64(u.(Int))
-					b := int64(v.(Int))
-					if a == 0 || b == 0 || a == b {
-						return bigOne
-					}
-					if a < 0 || b < 0 || a > b {
-						return bigZero
-					}
-					aFac := factorial(a)
-					bFac := factorial(b)
-					bMinusAFac := factorial(b - a)
-					bFac.Div(bFac, aFac)
-					bFac.Div(bFac, bMinusAFac)
-					return BigInt{bFac}.shrink()
-				},
+				intType:      binomial,
+				bigIntType:   binomial,
+				bigRatType:   floatBinomial,
+				bigFloatType: floatBinomial,
 			},
 		},
 
@@ -466,31 +661,39 @@ func init() {
 			elementwise: true,
 			whichType:   divType, // Shifts are like exp: let BigInt do the work.
 			fn: [numType]binaryFn{
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					z := bigInt64(0)
-					z.Lsh(i.Int, shiftCount(j))
-					return z.shrink()
-				},
+				bigIntType: lsh,
 				// TODO: lsh for bigfloat
 			},
 		},
 
+		{
+			name:        "shiftl",
+			elementwise: true,
+			whichType:   divType,
+			fn: [numType]binaryFn{
+				bigIntType: lsh,
+			},
+		},
+
 		{
 			name:        ">>",
 			elementwise: true,
 			whichType:   divType, // Shifts are like exp: let BigInt do the work.
 			fn: [numType]binaryFn{
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					z := bigInt64(0)
-					z.Rsh(i.Int, shiftCount(j))
-					return z.shrink()
-				},
+				bigIntType: rsh,
 				// TODO: rsh for bigfloat
 			},
 		},
 
+		{
+			name:        "shiftr",
+			elementwise: true,
+			whichType:   divType,
+			fn: [numType]binaryFn{
+				bigIntType: rsh,
+			},
+		},
+
 		{
 			name:        "==",
 			elementwise: true,
@@ -508,11 +711,14 @@ func init() {
 				},
 				bigRatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) == 0)
+					return toInt(ratCmp(c, i, j) == 0)
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) == 0)
+					return toInt(floatCmp(c, i, j) == 0)
+				},
+				complexType: func(c Context, u, v Value) Value {
+					return toInt(u.(Complex).equal(c, v.(Complex)))
 				},
 			},
 		},
@@ -534,11 +740,14 @@ func init() {
 				},
 				bigRatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) != 0)
+					return toInt(ratCmp(c, i, j) != 0)
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) != 0)
+					return toInt(floatCmp(c, i, j) != 0)
+				},
+				complexType: func(c Context, u, v Value) Value {
+					return toInt(!u.(Complex).equal(c, v.(Complex)))
 				},
 			},
 		},
@@ -560,11 +769,11 @@ func init() {
 				},
 				bigRatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) < 0)
+					return toInt(ratCmp(c, i, j) < 0)
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) < 0)
+					return toInt(floatCmp(c, i, j) < 0)
 				},
 			},
 		},
@@ -586,11 +795,11 @@ func init() {
 				},
 				bigRatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) <= 0)
+					return toInt(ratCmp(c, i, j) <= 0)
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) <= 0)
+					return toInt(floatCmp(c, i, j) <= 0)
 				},
 			},
 		},
@@ -612,11 +821,11 @@ func init() {
 				},
 				bigRatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) > 0)
+					return toInt(ratCmp(c, i, j) > 0)
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) > 0)
+					return toInt(floatCmp(c, i, j) > 0)
 				},
 			},
 		},
@@ -638,11 +847,11 @@ func init() {
 				},
 				bigRatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigRat), v.(BigRat)
-					return toInt(i.Cmp(j.Rat) >= 0)
+					return toInt(ratCmp(c, i, j) >= 0)
 				},
 				bigFloatType: func(c Context, u, v Value) Value {
 					i, j := u.(BigFloat), v.(BigFloat)
-					return toInt(i.Cmp(j.Float) >= 0)
+					return toInt(floatCmp(c, i, j) >= 0)
 				},
 			},
 		},
@@ -792,35 +1001,10 @@ func init() {
 			whichType: atLeastVectorType,
 			fn: [numType]binaryFn{
 				vectorType: func(c Context, u, v Value) Value {
-					// A decode B is the result of polyomial B at x=A.
-					// If A is a vector, the elements of A align with B.
-					A, B := u.(Vector), v.(Vector)
-					if len(A) == 0 || len(B) == 0 {
-						return Int(0)
-					}
-					if len(A) == 1 || len(B) == 1 || len(A) == len(B) {
-						result := Value(Int(0))
-						prod := Value(Int(1))
-						get := func(v Vector, i int) Value {
-							if len(v) == 1 {
-								return v[0]
-							}
-							return v[i]
-						}
-						n := len(A)
-						if len(B) > n {
-							n = len(B)
-						}
-						for i := n - 1; i >= 0; i-- {
-							result = c.EvalBinary(result, "+", c.EvalBinary(prod, "*", get(B, i)))
-							prod = c.EvalBinary(prod, "*", get(A, i))
-						}
-						return result
-					}
-					if len(A) != len(B) {
-						Errorf("decode of unequal lengths")
-					}
-					return nil
+					return decode(c, u.(Vector), v.(Vector))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return decodeMatrix(c, u.(*Matrix), v.(*Matrix))
 				},
 			},
 		},
@@ -838,41 +1022,22 @@ func init() {
 					// 1 0 1
 					// If they are negative the answers disagree with APL because
 					// of how modulo arithmetic works.
-					mod := func(b, a Value) Value {
-						if z, ok := a.(Int); ok && z == 0 {
-							return b
-						}
-						return c.EvalBinary(b, "mod", a)
-					}
-					div := func(b, a Value) Value {
-						if z, ok := a.(Int); ok && z == 0 {
-							return b
-						}
-						return c.EvalBinary(b, "div", a)
-					}
 					A, B := u.(Vector), v.(Vector)
 					// Scalar.
 					if len(A) == 1 && len(B) == 1 {
-						return mod(B[0], A[0])
+						return encodeMod(c, B[0], A[0])
 					}
 					// Vector.
 					if len(B) == 1 {
 						// 2 2 2 2 encode 11 is 1 0 1 1.
-						elems := make([]Value, len(A))
-						b := B[0]
-						for i := len(A) - 1; i >= 0; i-- {
-							a := A[i]
-							elems[i] = mod(b, a)
-							b = div(b, a)
-						}
-						return NewVector(elems)
+						return NewVector(encode(c, A, B[0]))
 					}
 					if len(A) == 1 {
 						// 3 encode 1 2 3 4 is 1 2 0 1
 						elems := make([]Value, len(B))
 						a := A[0]
 						for i := range B {
-							elems[i] = mod(B[i], a)
+							elems[i] = encodeMod(c, B[i], a)
 						}
 						return NewVector(elems)
 					}
@@ -880,17 +1045,10 @@ func init() {
 					// 2 2 encode 1 2 3 has 3 columns encoding 1 2 3 downwards:
 					// 0 1 1
 					// 1 0 1
-					elems := make([]Value, len(A)*len(B))
-					shape := []int{len(A), len(B)}
-					for j := range B {
-						b := B[j]
-						for i := len(A) - 1; i >= 0; i-- {
-							a := A[i]
-							elems[j+i*len(B)] = mod(b, a)
-							b = div(b, a)
-						}
-					}
-					return NewMatrix(shape, elems)
+					return encodeMatrix(c, NewMatrix([]int{len(A)}, A), NewMatrix([]int{len(B)}, B))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return encodeMatrix(c, u.(*Matrix), v.(*Matrix))
 				},
 			},
 		},
@@ -909,6 +1067,106 @@ func init() {
 			},
 		},
 
+		{
+			name: "dot",
+			// A dot B: same as A +.* B.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return innerProduct(c, u, "+", "*", v)
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return innerProduct(c, u, "+", "*", v)
+				},
+			},
+		},
+
+		{
+			name: "cross",
+			// A cross B: cross product of 3-vectors.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return cross(c, u.(Vector), v.(Vector))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					Errorf("cross: operands must be vectors")
+					return nil
+				},
+			},
+		},
+
+		{
+			name: "kron",
+			// A kron B: Kronecker product.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					A, B := u.(Vector), v.(Vector)
+					return kron(c, NewMatrix([]int{len(A)}, A), NewMatrix([]int{len(B)}, B)).data
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return kron(c, u.(*Matrix), v.(*Matrix))
+				},
+			},
+		},
+
+		{
+			name: "count",
+			// A count B: how many times each element of A occurs in B.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return count(c, u.(Vector), v.(Vector))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return count(c, u.(*Matrix).data, v.(*Matrix).data)
+				},
+			},
+		},
+
+		{
+			name: "union",
+			// A union B: the distinct elements of A and B.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return union(c, u.(Vector), v.(Vector))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return union(c, u.(*Matrix).data, v.(*Matrix).data)
+				},
+			},
+		},
+
+		{
+			name: "intersect",
+			// A intersect B: the distinct elements of A that are in B.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return intersect(c, u.(Vector), v.(Vector))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return intersect(c, u.(*Matrix).data, v.(*Matrix).data)
+				},
+			},
+		},
+
+		{
+			name: "diff",
+			// A diff B: the distinct elements of A that are not in B.
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return difference(c, u.(Vector), v.(Vector))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return difference(c, u.(*Matrix).data, v.(*Matrix).data)
+				},
+			},
+		},
+
 		{
 			name:      "[]",
 			whichType: binaryArithType,
@@ -1009,7 +1267,7 @@ func init() {
 			whichType: atLeastVectorType,
 			fn: [numType]binaryFn{
 				vectorType: func(c Context, u, v Value) Value {
-					// A⍳B: The location (index) of B in A; 0 if not found. (APL does 1+⌈/⍳⍴A)
+					// A⍳B: The location (index) of B in A; origin-1 if not found. (APL does 1+⌈/⍳⍴A)
 					A, B := u.(Vector), v.(Vector)
 					indices := make([]Value, len(B))
 					// TODO: This is n^2.
@@ -1017,12 +1275,12 @@ func init() {
 				Outer:
 					for i, b := range B {
 						for j, a := range A {
-							if toBool(c.EvalBinary(a, "==", b)) {
+							if sameValue(c, a, b) {
 								indices[i] = Int(j + origin)
 								continue Outer
 							}
 						}
-						indices[i] = zero
+						indices[i] = Int(origin - 1)
 					}
 					return NewVector(indices)
 				},
@@ -1030,84 +1288,67 @@ func init() {
 		},
 
 		{
+			// Special case, handled in EvalBinary: don't modify types.
 			name:        "min",
 			elementwise: true,
-			whichType:   binaryArithType,
+			whichType:   nil,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					if u.(Int) < v.(Int) {
-						return u
-					}
-					return v
-				},
-				charType: func(c Context, u, v Value) Value {
-					if u.(Char) < v.(Char) {
-						return u
-					}
-					return v
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					if i.Cmp(j.Int) < 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					if i.Cmp(j.Rat) < 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
-				bigFloatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigFloat), v.(BigFloat)
-					if i.Cmp(j.Float) < 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
+				0: func(c Context, u, v Value) Value { return minMax(c, "min", u, v) },
 			},
 		},
 
 		{
+			// Special case, handled in EvalBinary: don't modify types.
 			name:        "max",
 			elementwise: true,
+			whichType:   nil,
+			fn: [numType]binaryFn{
+				0: func(c Context, u, v Value) Value { return minMax(c, "max", u, v) },
+			},
+		},
+
+		{
+			name:        "round",
+			elementwise: true,
 			whichType:   binaryArithType,
 			fn: [numType]binaryFn{
-				intType: func(c Context, u, v Value) Value {
-					if u.(Int) > v.(Int) {
-						return u
-					}
-					return v
-				},
-				charType: func(c Context, u, v Value) Value {
-					if u.(Char) > v.(Char) {
-						return u
-					}
-					return v
-				},
-				bigIntType: func(c Context, u, v Value) Value {
-					i, j := u.(BigInt), v.(BigInt)
-					if i.Cmp(j.Int) > 0 {
-						return u
-					}
-					return v
-				},
-				bigRatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigRat), v.(BigRat)
-					if i.Cmp(j.Rat) > 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
-				bigFloatType: func(c Context, u, v Value) Value {
-					i, j := u.(BigFloat), v.(BigFloat)
-					if i.Cmp(j.Float) > 0 {
-						return i.shrink()
-					}
-					return j.shrink()
-				},
+				intType:      roundTo("round"),
+				bigIntType:   roundTo("round"),
+				bigRatType:   roundTo("round"),
+				bigFloatType: roundTo("round"),
+			},
+		},
+
+		{
+			name:        "floor",
+			elementwise: true,
+			whichType:   binaryArithType,
+			fn: [numType]binaryFn{
+				intType:      roundTo("floor"),
+				bigIntType:   roundTo("floor"),
+				bigRatType:   roundTo("floor"),
+				bigFloatType: roundTo("floor"),
+			},
+		},
+
+		{
+			name:        "ceil",
+			elementwise: true,
+			whichType:   binaryArithType,
+			fn: [numType]binaryFn{
+				intType:      roundTo("ceil"),
+				bigIntType:   roundTo("ceil"),
+				bigRatType:   roundTo("ceil"),
+				bigFloatType: roundTo("ceil"),
+			},
+		},
+
+		{
+			name:      "transp",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: transposeAxes,
+				matrixType: transposeAxes,
 			},
 		},
 
@@ -1160,68 +1401,43 @@ func init() {
 		{
 			name:      "take",
 			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: take,
+				matrixType: take,
+			},
+		},
+
+		{
+			name:      "drop",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: drop,
+				matrixType: drop,
+			},
+		},
+
+		{
+			name:      "up",
+			whichType: atLeastVectorType,
 			fn: [numType]binaryFn{
 				vectorType: func(c Context, u, v Value) Value {
-					const bad = Error("bad count for take")
-					i := v.(Vector)
-					nv, ok := u.(Vector)
-					if !ok || len(nv) != 1 {
-						panic(bad)
-					}
-					n, ok := nv[0].(Int)
-					if !ok {
-						panic(bad)
-					}
-					len := Int(len(i))
-					switch {
-					case n < 0:
-						if -n > len {
-							panic(bad)
-						}
-						i = i[len+n : len]
-					case n == 0:
-						return NewVector(nil)
-					case n > 0:
-						if n > len {
-							panic(bad)
-						}
-						i = i[0:n]
-					}
-					return i
+					return collate("up", u, v).(Vector).grade(c, false)
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return collate("up", u, v).(*Matrix).grade(c, false)
 				},
 			},
 		},
 
 		{
-			name:      "drop",
+			name:      "down",
 			whichType: atLeastVectorType,
 			fn: [numType]binaryFn{
 				vectorType: func(c Context, u, v Value) Value {
-					const bad = Error("bad count for drop")
-					i := v.(Vector)
-					nv, ok := u.(Vector)
-					if !ok || len(nv) != 1 {
-						panic(bad)
-					}
-					n, ok := nv[0].(Int)
-					if !ok {
-						panic(bad)
-					}
-					len := Int(len(i))
-					switch {
-					case n < 0:
-						if -n > len {
-							panic(bad)
-						}
-						i = i[0 : len+n]
-					case n == 0:
-					case n > 0:
-						if n > len {
-							panic(bad)
-						}
-						i = i[n:]
-					}
-					return i
+					return collate("down", u, v).(Vector).grade(c, true)
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return collate("down", u, v).(*Matrix).grade(c, true)
 				},
 			},
 		},
@@ -1341,6 +1557,22 @@ func init() {
 					}
 					return NewVector(result)
 				},
+				matrixType: func(c Context, u, v Value) Value {
+					return Expand(c, "fill", u, v, nil)
+				},
+			},
+		},
+
+		{
+			name:      "\\",
+			whichType: atLeastVectorType,
+			fn: [numType]binaryFn{
+				vectorType: func(c Context, u, v Value) Value {
+					return Expand(c, "\\", u, v, nil)
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return Expand(c, "\\", u, v, nil)
+				},
 			},
 		},
 
@@ -1395,6 +1627,28 @@ func init() {
 					}
 					return NewVector(result)
 				},
+				matrixType: func(c Context, u, v Value) Value {
+					return Replicate(c, u, v, nil)
+				},
+			},
+		},
+
+		{
+			name:      "inv",
+			whichType: binaryArithType,
+			fn: [numType]binaryFn{
+				intType:      divideScalars,
+				bigIntType:   divideScalars,
+				bigRatType:   divideScalars,
+				bigFloatType: divideScalars,
+				complexType:  divideScalars,
+				vectorType: func(c Context, u, v Value) Value {
+					a, b := u.(Vector), v.(Vector)
+					return solve(c, NewMatrix([]int{len(a)}, a), NewMatrix([]int{len(b)}, b))
+				},
+				matrixType: func(c Context, u, v Value) Value {
+					return solve(c, u.(*Matrix), v.(*Matrix))
+				},
 			},
 		},
 
@@ -1407,6 +1661,79 @@ func init() {
 				0: fmtText,
 			},
 		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "setenv",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: setenv,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "csv",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: writeCSV,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "match",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: match,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "find",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: find,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "polyval",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: polyval,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "mpow",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: matrixPower,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:      "select",
+			whichType: nil,
+			fn: [numType]binaryFn{
+				0: selectMask,
+			},
+		},
+
+		{
+			// Special case, handled in EvalBinary: don't modify types.
+			name:        "approx",
+			elementwise: true,
+			whichType:   nil,
+			fn: [numType]binaryFn{
+				0: approx,
+			},
+		},
 	}
 
 	for _, op := range ops {
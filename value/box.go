@@ -0,0 +1,269 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"robpike.io/ivy/config"
+)
+
+// Box is an enclosed value: a scalar holding an array, so that arrays
+// may be elements of other arrays. Boxes may hold boxes, to any depth.
+type Box struct {
+	value Value
+}
+
+// enclose returns v enclosed in a box. A simple scalar encloses
+// to itself.
+func enclose(c Context, v Value) Value {
+	switch v.(type) {
+	case Box, Vector, *Matrix:
+		return Box{v}
+	}
+	return v
+}
+
+// disclose returns the contents of the box v. For a vector holding
+// boxes, it returns the matrix whose rows are their contents, padded
+// as by take to the length of the longest. Other values are returned
+// unchanged.
+func disclose(c Context, v Value) Value {
+	switch v := v.(type) {
+	case Box:
+		return v.value
+	case Vector:
+		if !v.hasBox() {
+			return v
+		}
+		items := make([]Value, len(v))
+		width := 0
+		for i, x := range v {
+			if b, ok := x.(Box); ok {
+				x = b.value
+			}
+			switch x.(type) {
+			case Vector:
+			case *Matrix, Box:
+				Errorf("disclose: items must be scalars or vectors")
+			default:
+				x = NewVector([]Value{x})
+			}
+			if n := len(x.(Vector)); n > width {
+				width = n
+			}
+			items[i] = x
+		}
+		data := make(Vector, 0, len(v)*width)
+		for _, x := range items {
+			data = append(data, take(c, NewIntVector([]int{width}), x).(Vector)...)
+		}
+		return NewMatrix([]int{len(v), width}, data)
+	case *Matrix:
+		if v.data.hasBox() {
+			Errorf("disclose: matrix of boxes not implemented")
+		}
+	}
+	return v
+}
+
+// depth returns the depth of nesting of v: 0 for a simple scalar, 1 for
+// an array of simple scalars, and one more than the deepest item of an
+// array that holds boxes. An enclosed array is one deeper than the array.
+func depth(v Value) int {
+	switch v := v.(type) {
+	case Box:
+		return 1 + depth(v.value)
+	case Vector:
+		return 1 + itemDepth(v)
+	case *Matrix:
+		return 1 + itemDepth(v.data)
+	}
+	return 0
+}
+
+// itemDepth returns the greatest depth of the contents of the items of v.
+func itemDepth(v Vector) int {
+	d := 0
+	for _, x := range v {
+		if b, ok := x.(Box); ok {
+			x = b.value
+		}
+		if dx := depth(x); dx > d {
+			d = dx
+		}
+	}
+	return d
+}
+
+func (b Box) String() string {
+	return "(" + b.Sprint(debugConf) + ")"
+}
+
+func (b Box) Rank() int {
+	return 0
+}
+
+// Sprint draws a frame around the printed contents of the box.
+func (b Box) Sprint(conf *config.Config) string {
+	lines := newBlock(b.value.Sprint(conf))
+	width := lines.width()
+	var s strings.Builder
+	s.WriteString("┌" + strings.Repeat("─", width) + "┐\n")
+	for _, line := range lines {
+		s.WriteString("│" + padRight(line, width) + "│\n")
+	}
+	s.WriteString("└" + strings.Repeat("─", width) + "┘")
+	return s.String()
+}
+
+func (b Box) ProgString() string {
+	// There is no literal syntax for boxes.
+	panic("box.ProgString - cannot happen")
+}
+
+func (b Box) Eval(Context) Value {
+	return b
+}
+
+func (b Box) Inner() Value {
+	return b
+}
+
+// Contents returns the value enclosed in the box.
+func (b Box) Contents() Value {
+	return b.value
+}
+
+func (b Box) toType(conf *config.Config, which valueType) Value {
+	switch which {
+	case boxType:
+		return b
+	case vectorType:
+		return NewVector([]Value{b})
+	case matrixType:
+		return NewMatrix([]int{1}, []Value{b})
+	}
+	Errorf("cannot convert box to %s", which)
+	return nil
+}
+
+// hasBox reports whether any element of the vector is a box.
+func (v Vector) hasBox() bool {
+	for _, x := range v {
+		if _, ok := x.(Box); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// padRight pads s with spaces to the given width in runes.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+// padLeft pads s on the left with spaces to the given width in runes.
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", width-utf8.RuneCountInString(s)) + s
+}
+
+// A block is printed text that may occupy several lines.
+type block []string
+
+// newBlock splits the printed text s into a block.
+func newBlock(s string) block {
+	return strings.Split(s, "\n")
+}
+
+// width returns the width in runes of the widest line of the block.
+func (b block) width() int {
+	w := 0
+	for _, line := range b {
+		if n := utf8.RuneCountInString(line); n > w {
+			w = n
+		}
+	}
+	return w
+}
+
+// line returns line i of the block when it is centered vertically in a
+// space of the given height, right-justified to the given width.
+func (b block) line(i, height, width int) string {
+	i -= (height - len(b)) / 2
+	if i < 0 || i >= len(b) {
+		return strings.Repeat(" ", width)
+	}
+	return padLeft(b[i], width)
+}
+
+// joinBlocks lays out the rows of blocks as a table, with the blocks
+// of each column right-justified to a common width and separated by
+// a space. The rows are separated by newlines, and trailing spaces
+// are trimmed.
+func joinBlocks(rows [][]block) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for j, b := range row {
+			if w := b.width(); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+	var lines []string
+	for _, row := range rows {
+		height := 0
+		for _, b := range row {
+			if len(b) > height {
+				height = len(b)
+			}
+		}
+		for i := 0; i < height; i++ {
+			parts := make([]string, len(row))
+			for j, b := range row {
+				parts[j] = b.line(i, height, widths[j])
+			}
+			lines = append(lines, strings.TrimRight(strings.Join(parts, " "), " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// boxString prints a vector that holds boxes, laying out the
+// printed elements side by side.
+func (v Vector) boxString(conf *config.Config) string {
+	row := make([]block, len(v))
+	for i, x := range v {
+		row[i] = newBlock(x.Sprint(conf))
+	}
+	return joinBlocks([][]block{row})
+}
+
+// boxString prints a matrix that holds boxes, as a table of the
+// printed elements for each 2-dimensional slice.
+func (m *Matrix) boxString(conf *config.Config) string {
+	ncols := m.shape[len(m.shape)-1]
+	nrows := m.shape[len(m.shape)-2]
+	size := nrows * ncols
+	if size == 0 {
+		return ""
+	}
+	var slices []string
+	for start := 0; start < len(m.data); start += size {
+		rows := make([][]block, nrows)
+		for i := range rows {
+			rows[i] = make([]block, ncols)
+			for j := range rows[i] {
+				rows[i][j] = newBlock(m.data[start+i*ncols+j].Sprint(conf))
+			}
+		}
+		slices = append(slices, joinBlocks(rows))
+	}
+	return strings.Join(slices, "\n\n")
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// Continued fractions. A number x is written as
+//
+//	a0 + 1/(a1 + 1/(a2 + ...))
+//
+// with integer terms, all positive after the first.
+
+// cfrac returns the continued-fraction terms of v. The expansion of a
+// rational is exact and finite. A float is expanded only until the
+// convergent equals it to the float precision, since later terms
+// describe the rounding error rather than the number.
+func cfrac(c Context, v Value) Value {
+	var x *big.Rat
+	var f *big.Float
+	switch v := v.(type) {
+	case Int, BigInt:
+		return NewVector([]Value{v})
+	case BigRat:
+		x = v.Rat
+	case BigFloat:
+		if v.IsInf() {
+			Errorf("cfrac of %s", v.Sprint(c.Config()))
+		}
+		x, _ = v.Rat(nil)
+		f = v.Float
+	default:
+		Errorf("cfrac: argument must be a real number")
+	}
+	var terms []Value
+	num := new(big.Int).Set(x.Num())
+	den := new(big.Int).Set(x.Denom())
+	// The convergents p/q are built by the usual recurrence
+	// from the two before, starting with 0/1 and 1/0.
+	p0, q0 := big.NewInt(0), big.NewInt(1)
+	p1, q1 := big.NewInt(1), big.NewInt(0)
+	for den.Sign() != 0 {
+		a, m := new(big.Int).DivMod(num, den, new(big.Int))
+		pn := new(big.Int).Mul(a, p1)
+		qn := new(big.Int).Mul(a, q1)
+		p0, p1 = p1, pn.Add(pn, p0)
+		q0, q1 = q1, qn.Add(qn, q0)
+		terms = append(terms, BigInt{a}.shrink())
+		num, den = den, m
+		if f != nil && newFloat(c).SetRat(new(big.Rat).SetFrac(p1, q1)).Cmp(f) == 0 {
+			break
+		}
+	}
+	return NewVector(terms)
+}
+
+// fromcfrac returns the number whose continued-fraction terms are v.
+func fromcfrac(c Context, v Vector) Value {
+	if len(v) == 0 {
+		Errorf("fromcfrac: empty vector")
+	}
+	for _, t := range v {
+		switch t.(type) {
+		case Int, BigInt:
+		default:
+			Errorf("fromcfrac: terms must be integers")
+		}
+	}
+	x := v[len(v)-1]
+	for i := len(v) - 2; i >= 0; i-- {
+		x = c.EvalBinary(v[i], "+", c.EvalBinary(one, "/", x))
+	}
+	return x
+}
+
+// approx returns the best rational approximation to each element of
+// v with denominator no larger than u. The candidates are the last
+// convergent of the continued fraction within the bound and the
+// largest semiconvergent after it.
+func approx(c Context, u, v Value) Value {
+	var d *big.Int
+	switch u := u.(type) {
+	case Int:
+		d = big.NewInt(int64(u))
+	case BigInt:
+		d = u.Int
+	}
+	if d == nil || d.Sign() <= 0 {
+		Errorf("approx: left operand must be a positive integer")
+	}
+	switch v := v.(type) {
+	case Vector:
+		return approxVector(c, d, v)
+	case *Matrix:
+		return NewMatrix(v.shape, approxVector(c, d, v.data))
+	}
+	return approxValue(c, d, v)
+}
+
+func approxVector(c Context, d *big.Int, v Vector) Vector {
+	w := make(Vector, len(v))
+	for i, x := range v {
+		w[i] = approxValue(c, d, x)
+	}
+	return w
+}
+
+func approxValue(c Context, d *big.Int, v Value) Value {
+	var x *big.Rat
+	switch v := v.(type) {
+	case Int, BigInt:
+		return v
+	case BigRat:
+		x = v.Rat
+	case BigFloat:
+		if v.IsInf() {
+			Errorf("approx of %s", v.Sprint(c.Config()))
+		}
+		x, _ = v.Rat(nil)
+	default:
+		Errorf("approx: right operand must be real numbers")
+	}
+	num := new(big.Int).Set(x.Num())
+	den := new(big.Int).Set(x.Denom())
+	p0, q0 := big.NewInt(0), big.NewInt(1)
+	p1, q1 := big.NewInt(1), big.NewInt(0)
+	for den.Sign() != 0 {
+		a, m := new(big.Int).DivMod(num, den, new(big.Int))
+		qn := new(big.Int).Mul(a, q1)
+		qn.Add(qn, q0)
+		if qn.Cmp(d) > 0 {
+			// Take as many of a as fit under the bound.
+			k := new(big.Int).Sub(d, q0)
+			k.Quo(k, q1)
+			ps := new(big.Int).Mul(k, p1)
+			qs := new(big.Int).Mul(k, q1)
+			semi := new(big.Rat).SetFrac(ps.Add(ps, p0), qs.Add(qs, q0))
+			conv := new(big.Rat).SetFrac(p1, q1)
+			if ratDist(x, semi).Cmp(ratDist(x, conv)) < 0 {
+				return BigRat{semi}.shrink()
+			}
+			return BigRat{conv}.shrink()
+		}
+		pn := new(big.Int).Mul(a, p1)
+		p0, p1 = p1, pn.Add(pn, p0)
+		q0, q1 = q1, qn
+		num, den = den, m
+	}
+	return BigRat{new(big.Rat).SetFrac(p1, q1)}.shrink()
+}
+
+// ratDist returns |x-y|.
+func ratDist(x, y *big.Rat) *big.Rat {
+	z := new(big.Rat).Sub(x, y)
+	return z.Abs(z)
+}
@@ -6,7 +6,10 @@ package value
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"robpike.io/ivy/config"
@@ -53,19 +56,94 @@ func (c Char) toType(conf *config.Config, which valueType) Value {
 	return nil
 }
 
-func (c Char) validate() Char {
-	if !utf8.ValidRune(rune(c)) {
-		Errorf("invalid char value %U\n", c)
+// newChar returns the char with code point v, an integer. Surrogates
+// and values outside the Unicode range are rejected, as is a float
+// that is not an integer: char does not round.
+func newChar(c Context, v Value) Value {
+	if f, ok := v.(BigFloat); ok && f.IsInt() {
+		i, _ := f.Int(nil)
+		v = BigInt{i}
 	}
-	return c
+	n := bigIntOf("char", v)
+	if n.Sign() < 0 || n.Cmp(big.NewInt(unicode.MaxRune)) > 0 || !utf8.ValidRune(rune(n.Int64())) {
+		Errorf("char: invalid code point %#x", n)
+	}
+	return Char(n.Int64())
+}
+
+// goString returns the text of v, which must be a char or a vector of
+// chars. The name is used in error messages.
+func goString(name string, v Value) string {
+	switch v := v.(type) {
+	case Char:
+		return string(v)
+	case Vector:
+		if v.AllChars() {
+			return v.makeString(debugConf, false)
+		}
+	}
+	Errorf("%s: argument must be a string", name)
+	return ""
+}
+
+// trim returns v, a string or char matrix, without leading and
+// trailing white space. The rows of a matrix are trimmed separately and
+// padded with blanks on the right to the length of the longest.
+func trim(c Context, v Value) Value {
+	switch v := v.(type) {
+	case Char:
+		if unicode.IsSpace(rune(v)) {
+			return NewVector([]Value{})
+		}
+		return v
+	case Vector:
+		return newString(strings.TrimSpace(goString("trim", v)))
+	case *Matrix:
+		ncols := v.shape[len(v.shape)-1]
+		nrows := 1
+		if ncols > 0 {
+			nrows = len(v.data) / ncols
+		}
+		rows := make([]string, nrows)
+		width := 0
+		for i := range rows {
+			rows[i] = strings.TrimSpace(goString("trim", NewVector(v.data[i*ncols:(i+1)*ncols])))
+			if n := utf8.RuneCountInString(rows[i]); n > width {
+				width = n
+			}
+		}
+		data := make([]Value, 0, nrows*width)
+		for _, row := range rows {
+			pad := width - utf8.RuneCountInString(row)
+			data = append(data, newString(row+strings.Repeat(" ", pad))...)
+		}
+		shape := append([]int{}, v.shape...)
+		shape[len(shape)-1] = width
+		return NewMatrix(shape, data)
+	}
+	Errorf("trim: argument must be a string")
+	return nil
+}
+
+// newString returns the vector of the chars of s.
+func newString(s string) Vector {
+	elem := make([]Value, 0, len(s))
+	for _, r := range s {
+		elem = append(elem, Char(r))
+	}
+	return NewVector(elem)
 }
 
 // ParseString parses a string. Single quotes and
 // double quotes are both allowed (but must be consistent.)
+// Escapes are as in Go: \n, \t, \\, \", \xHH, \uHHHH and so on.
 // The result must contain only valid Unicode code points.
 func ParseString(s string) string {
 	str, ok := unquote(s)
 	if !ok {
+		if pos, esc := badEscape(s); esc != "" {
+			Errorf("invalid escape sequence %s at position %d of %s", esc, pos, s)
+		}
 		Errorf("invalid string syntax")
 	}
 	if !utf8.ValidString(str) {
@@ -125,6 +203,28 @@ func unquote(s string) (t string, ok bool) {
 	return string(buf), true
 }
 
+// badEscape returns the text of the first invalid escape sequence in
+// the quoted string s, and its position, counting in runes from 1 at
+// the opening quote. If there is none, it returns the empty string.
+func badEscape(s string) (int, string) {
+	if len(s) < 2 || s[0] != '"' && s[0] != '\'' {
+		return 0, ""
+	}
+	for t := s[1 : len(s)-1]; len(t) > 0; {
+		_, _, rest, err := strconv.UnquoteChar(t, s[0])
+		if err != nil {
+			if t[0] != '\\' {
+				return 0, ""
+			}
+			_, w := utf8.DecodeRuneInString(t[1:])
+			pos := utf8.RuneCountInString(s[:len(s)-1-len(t)]) + 1
+			return pos, t[:1+w]
+		}
+		t = rest
+	}
+	return 0, ""
+}
+
 // contains reports whether the string contains the byte c.
 func contains(s string, c byte) bool {
 	for i := 0; i < len(s); i++ {
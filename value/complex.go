@@ -0,0 +1,174 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"strings"
+
+	"robpike.io/ivy/config"
+)
+
+// Complex is a complex number. The real and imaginary parts are
+// real scalars of any numeric type, so arithmetic on them is exact
+// when possible.
+type Complex struct {
+	real, imag Value
+}
+
+// newComplex returns the complex number with the given real and
+// imaginary parts. If the imaginary part is zero, it returns the real part.
+func newComplex(real, imag Value) Value {
+	if !toBool(imag) {
+		return real
+	}
+	return Complex{real, imag}
+}
+
+// parseComplex parses a complex literal such as 3j4. The text before
+// and after the j must each be a valid real number.
+func parseComplex(conf *config.Config, s string) (Value, error) {
+	j := strings.IndexByte(s, 'j')
+	real, err := Parse(conf, s[:j])
+	if err != nil {
+		return nil, err
+	}
+	imag, err := Parse(conf, s[j+1:])
+	if err != nil {
+		return nil, err
+	}
+	return newComplex(real, imag), nil
+}
+
+// Real returns the real part of z.
+func (z Complex) Real() Value {
+	return z.real
+}
+
+// Imag returns the imaginary part of z.
+func (z Complex) Imag() Value {
+	return z.imag
+}
+
+func (z Complex) Rank() int {
+	return 0
+}
+
+func (z Complex) String() string {
+	return "(" + z.Sprint(debugConf) + ")"
+}
+
+func (z Complex) Sprint(conf *config.Config) string {
+	return z.real.Sprint(conf) + "j" + z.imag.Sprint(conf)
+}
+
+func (z Complex) ProgString() string {
+	return z.real.ProgString() + "j" + z.imag.ProgString()
+}
+
+func (z Complex) Eval(Context) Value {
+	return z
+}
+
+func (z Complex) Inner() Value {
+	return z
+}
+
+func (z Complex) toType(conf *config.Config, which valueType) Value {
+	switch which {
+	case complexType:
+		return z
+	case vectorType:
+		return NewVector([]Value{z})
+	case matrixType:
+		return NewMatrix([]int{1}, []Value{z})
+	}
+	Errorf("cannot convert complex to %s", which)
+	return nil
+}
+
+func (z Complex) neg(c Context) Value {
+	return newComplex(c.EvalUnary("-", z.real), c.EvalUnary("-", z.imag))
+}
+
+func (z Complex) conj(c Context) Value {
+	return newComplex(z.real, c.EvalUnary("-", z.imag))
+}
+
+// abs returns the magnitude of z.
+func (z Complex) abs(c Context) Value {
+	return c.EvalUnary("sqrt", z.norm(c))
+}
+
+// norm returns the square of the magnitude of z.
+func (z Complex) norm(c Context) Value {
+	re := c.EvalBinary(z.real, "*", z.real)
+	im := c.EvalBinary(z.imag, "*", z.imag)
+	return c.EvalBinary(re, "+", im)
+}
+
+// exp returns e to the power z.
+func (z Complex) exp(c Context) Value {
+	mag := c.EvalUnary("**", z.real)
+	re := c.EvalBinary(mag, "*", c.EvalUnary("cos", z.imag))
+	im := c.EvalBinary(mag, "*", c.EvalUnary("sin", z.imag))
+	return newComplex(re, im)
+}
+
+func (z Complex) add(c Context, w Complex) Value {
+	return newComplex(c.EvalBinary(z.real, "+", w.real), c.EvalBinary(z.imag, "+", w.imag))
+}
+
+func (z Complex) sub(c Context, w Complex) Value {
+	return newComplex(c.EvalBinary(z.real, "-", w.real), c.EvalBinary(z.imag, "-", w.imag))
+}
+
+func (z Complex) mul(c Context, w Complex) Value {
+	// (a+bi)(c+di) = (ac-bd) + (ad+bc)i
+	ac := c.EvalBinary(z.real, "*", w.real)
+	bd := c.EvalBinary(z.imag, "*", w.imag)
+	ad := c.EvalBinary(z.real, "*", w.imag)
+	bc := c.EvalBinary(z.imag, "*", w.real)
+	return newComplex(c.EvalBinary(ac, "-", bd), c.EvalBinary(ad, "+", bc))
+}
+
+func (z Complex) quo(c Context, w Complex) Value {
+	// (a+bi)/(c+di) = ((ac+bd) + (bc-ad)i) / (c²+d²)
+	den := w.norm(c)
+	if !toBool(den) {
+		Errorf("division by zero")
+	}
+	ac := c.EvalBinary(z.real, "*", w.real)
+	bd := c.EvalBinary(z.imag, "*", w.imag)
+	ad := c.EvalBinary(z.real, "*", w.imag)
+	bc := c.EvalBinary(z.imag, "*", w.real)
+	re := c.EvalBinary(c.EvalBinary(ac, "+", bd), "/", den)
+	im := c.EvalBinary(c.EvalBinary(bc, "-", ad), "/", den)
+	return newComplex(re, im)
+}
+
+// pow returns z to the power w, which must be an integer.
+func (z Complex) pow(c Context, w Complex) Value {
+	n, ok := w.real.(Int)
+	if toBool(w.imag) || !ok {
+		Errorf("complex power must be an integer")
+	}
+	var result Value = one
+	var base Value = z
+	if n < 0 {
+		base = c.EvalUnary("/", z)
+		n = -n
+	}
+	for ; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			result = c.EvalBinary(result, "*", base)
+		}
+		base = c.EvalBinary(base, "*", base)
+	}
+	return result
+}
+
+func (z Complex) equal(c Context, w Complex) bool {
+	return toBool(c.EvalBinary(z.real, "==", w.real)) && toBool(c.EvalBinary(z.imag, "==", w.imag))
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+
+	"robpike.io/ivy/config"
+)
+
+// Comma-separated values. The unary csv reads a file into a matrix,
+// one row per record, and the binary csv writes a matrix to a file.
+
+// readCSV returns the contents of the named CSV file as a matrix. Cells
+// that parse as numbers become numbers; others become enclosed strings.
+// Every record must have the same number of fields.
+func readCSV(c Context, v Value) Value {
+	file := goString("csv", v)
+	fd, err := os.Open(file)
+	if err != nil {
+		Errorf("csv: %s", err)
+	}
+	defer fd.Close()
+	records, err := csv.NewReader(bufio.NewReader(fd)).ReadAll()
+	if err != nil {
+		Errorf("csv: %s: %s", file, err)
+	}
+	if len(records) == 0 {
+		return Vector{}
+	}
+	conf := c.Config()
+	data := make([]Value, 0, len(records)*len(records[0]))
+	for _, record := range records {
+		for _, field := range record {
+			data = append(data, csvCell(conf, field))
+		}
+	}
+	return NewMatrix([]int{len(records), len(records[0])}, NewVector(data))
+}
+
+// csvCell returns the value of a CSV field: a number if it parses as
+// one, and otherwise an enclosed string.
+func csvCell(conf *config.Config, field string) Value {
+	if x, ok := parseNumber(conf, strings.TrimSpace(field)); ok {
+		return x
+	}
+	return Box{newString(field)}
+}
+
+// writeCSV writes u, a matrix, vector or scalar, to the file named by v
+// as CSV, one row per record, and returns the number of records. Strings
+// are written as their text and numbers as they are printed. The file
+// "<conf.out>" is the configured output, for testing.
+func writeCSV(c Context, u, v Value) Value {
+	file := goString("csv", v)
+	var rows [][]Value
+	switch u := u.(type) {
+	case *Matrix:
+		if u.Rank() != 2 {
+			Errorf("csv: cannot write rank %d matrix", u.Rank())
+		}
+		ncols := u.shape[1]
+		for i := 0; i < u.shape[0]; i++ {
+			rows = append(rows, u.data[i*ncols:(i+1)*ncols])
+		}
+	case Vector:
+		rows = [][]Value{u}
+	default:
+		rows = [][]Value{{u}}
+	}
+	conf := c.Config()
+	var out io.Writer = conf.Output()
+	if file != "<conf.out>" {
+		fd, err := os.Create(file)
+		if err != nil {
+			Errorf("csv: %s", err)
+		}
+		defer fd.Close()
+		out = fd
+	}
+	w := csv.NewWriter(out)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, x := range row {
+			record[i] = csvField(c, x)
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		Errorf("csv: %s", err)
+	}
+	return Int(len(rows))
+}
+
+// csvField returns the text of a CSV field holding x.
+func csvField(c Context, x Value) string {
+	switch x := x.(type) {
+	case Box:
+		return csvField(c, x.value)
+	case Char:
+		return string(x)
+	case Vector:
+		if x.AllChars() {
+			return x.makeString(debugConf, false)
+		}
+	}
+	if x.Rank() > 0 {
+		Errorf("csv: field must be a scalar or string")
+	}
+	return x.Sprint(c.Config())
+}
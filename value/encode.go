@@ -0,0 +1,78 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"unicode/utf8"
+)
+
+// Byte encodings. The unary base64 and hex encode a string, as UTF-8,
+// or a vector of bytes as the integers 0 through 255; unbase64 and
+// unhex decode the result, yielding a string if the bytes are valid
+// UTF-8 and otherwise a vector of bytes.
+
+// encodeBytes returns the bytes of v, a string or a vector of bytes.
+func encodeBytes(name string, v Value) []byte {
+	switch v := v.(type) {
+	case Char:
+		return []byte(string(v))
+	case Int:
+		return encodeBytes(name, NewVector([]Value{v}))
+	case Vector:
+		if len(v) > 0 && v.AllChars() {
+			return []byte(goString(name, v))
+		}
+		b := make([]byte, len(v))
+		for i, x := range v {
+			n, ok := x.(Int)
+			if !ok || n < 0 || n > 255 {
+				Errorf("%s: argument must be a string or bytes", name)
+			}
+			b[i] = byte(n)
+		}
+		return b
+	}
+	Errorf("%s: argument must be a string or bytes", name)
+	return nil
+}
+
+// decodedBytes returns b as a string if it is valid UTF-8, or else as
+// a vector of bytes.
+func decodedBytes(b []byte) Value {
+	if utf8.Valid(b) {
+		return newString(string(b))
+	}
+	elem := make([]Value, len(b))
+	for i, x := range b {
+		elem[i] = Int(x)
+	}
+	return NewVector(elem)
+}
+
+func encodeBase64(c Context, v Value) Value {
+	return newString(base64.StdEncoding.EncodeToString(encodeBytes("base64", v)))
+}
+
+func decodeBase64(c Context, v Value) Value {
+	b, err := base64.StdEncoding.DecodeString(goString("unbase64", v))
+	if err != nil {
+		Errorf("unbase64: %s", err)
+	}
+	return decodedBytes(b)
+}
+
+func encodeHex(c Context, v Value) Value {
+	return newString(hex.EncodeToString(encodeBytes("hex", v)))
+}
+
+func decodeHex(c Context, v Value) Value {
+	b, err := hex.DecodeString(goString("unhex", v))
+	if err != nil {
+		Errorf("unhex: %s", err)
+	}
+	return decodedBytes(b)
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "os"
+
+// getenv returns the value of the environment variable named by v, or
+// an empty string if it is not set.
+func getenv(c Context, v Value) Value {
+	return newString(os.Getenv(goString("getenv", v)))
+}
+
+// setenv sets the environment variable named by u to the string v,
+// and returns v.
+func setenv(c Context, u, v Value) Value {
+	name := goString("setenv", u)
+	if err := os.Setenv(name, goString("setenv", v)); err != nil {
+		Errorf("%s", err)
+	}
+	return v
+}
@@ -16,12 +16,15 @@ const (
 	bigIntType
 	bigRatType
 	bigFloatType
+	complexType
+	boxType
+	timeType
 	vectorType
 	matrixType
 	numType
 )
 
-var typeName = [...]string{"int", "char", "big int", "rational", "float", "vector", "matrix"}
+var typeName = [...]string{"int", "char", "big int", "rational", "float", "complex", "box", "time", "vector", "matrix"}
 
 func (t valueType) String() string {
 	return typeName[t]
@@ -36,7 +39,18 @@ type unaryOp struct {
 }
 
 func (op *unaryOp) EvalUnary(c Context, v Value) Value {
+	if r, ok := v.(Range); ok {
+		if x, ok := r.unary(c, op.name); ok {
+			return x
+		}
+		v = r.materialize(c.Config())
+	}
 	which := whichType(v)
+	if op.elementwise && which == bigFloatType {
+		if r, ok := specialUnary(op.name, v); ok {
+			return r
+		}
+	}
 	fn := op.fn[which]
 	if fn == nil {
 		if op.elementwise {
@@ -49,6 +63,9 @@ func (op *unaryOp) EvalUnary(c Context, v Value) Value {
 		}
 		Errorf("unary %s not implemented on type %s", op.name, which)
 	}
+	if op.elementwise {
+		return wrap(c, fn(c, v))
+	}
 	return fn(c, v)
 }
 
@@ -73,7 +90,13 @@ func whichType(v Value) valueType {
 		return bigRatType
 	case BigFloat:
 		return bigFloatType
-	case Vector:
+	case Complex:
+		return complexType
+	case Box:
+		return boxType
+	case Time:
+		return timeType
+	case Vector, Range:
 		return vectorType
 	case *Matrix:
 		return matrixType
@@ -82,16 +105,31 @@ func whichType(v Value) valueType {
 	panic("which type")
 }
 
-func (op *binaryOp) EvalBinary(c Context, u, v Value) Value {
+func (op *binaryOp) EvalBinary(c Context, u, v Value) (result Value) {
+	if x, ok := rangeBinary(c, u, op.name, v); ok {
+		return x
+	}
+	u, v = Materialize(c.Config(), u), Materialize(c.Config(), v)
 	if op.whichType == nil {
-		// At the moment, "text" is the only operator that leaves
-		// both arg types alone. Perhaps more will arrive.
-		if op.name != "text" {
+		// Operators such as "text" and "approx" leave both
+		// arg types alone and handle them all in fn[0].
+		if op.fn[0] == nil {
 			Errorf("internal error: nil whichType")
 		}
 		return op.fn[0](c, u, v)
 	}
 	which := op.whichType(whichType(u), whichType(v))
+	if which == timeType {
+		return timeBinary(c, u, op.name, v)
+	}
+	if op.elementwise && which < vectorType {
+		if r, ok := specialBinary(op.name, u, v); ok {
+			return r
+		}
+	}
+	if which == bigFloatType {
+		defer catchNaN(c, &result)
+	}
 	conf := c.Config()
 	u = u.toType(conf, which)
 	v = v.toType(conf, which)
@@ -107,13 +145,15 @@ func (op *binaryOp) EvalBinary(c Context, u, v Value) Value {
 		}
 		Errorf("binary %s not implemented on type %s", op.name, which)
 	}
+	if op.elementwise {
+		return wrap(c, fn(c, u, v))
+	}
 	return fn(c, u, v)
 }
 
 // Product computes a compound product, such as an inner product
 // "+.*" or outer product "o.*". The op is known to contain a
-// period. The operands are all at least vectors, and for inner product
-// they must both be vectors.
+// period. The operands are all at least vectors.
 func Product(c Context, u Value, op string, v Value) Value {
 	dot := strings.IndexByte(op, '.')
 	left := op[:dot]
@@ -129,58 +169,61 @@ func Product(c Context, u Value, op string, v Value) Value {
 
 // inner product computes an inner product such as "+.*".
 // u and v are known to be the same type and at least Vectors.
+// Either operator may be user-defined; if the left one is and yields
+// arrays, the elements are enclosed. The last axis of u must
+// match the first axis of v, and the result has the remaining axes
+// of u followed by the remaining axes of v.
 func innerProduct(c Context, u Value, left, right string, v Value) Value {
-	switch u := u.(type) {
-	case Vector:
-		v := v.(Vector)
-		u.sameLength(v)
-		var x Value
-		for k, e := range u {
-			tmp := c.EvalBinary(e, right, v[k])
-			if k == 0 {
-				x = tmp
-			} else {
-				x = c.EvalBinary(x, left, tmp)
-			}
-		}
-		return x
-	case *Matrix:
-		// Say we're doing +.*
-		// result[i,j] = +/(u[row i] * v[column j])
-		// Number of columns of u must be the number of rows of v.
-		// The result is has shape (urows, vcols).
-		v := v.(*Matrix)
-		if u.Rank() != 2 || v.Rank() != 2 {
-			Errorf("can't do inner product on shape %s times %s", NewIntVector(u.shape), NewIntVector(v.shape))
-		}
-		urows := u.shape[0]
-		ucols := u.shape[1]
-		vrows := v.shape[0]
-		vcols := v.shape[1]
-		if vrows != ucols {
-			Errorf("inner product; column count of left (%d) not equal to row count on right (%d)", ucols, vrows)
-		}
-		data := make(Vector, urows*vcols)
-		shape := []int{urows, vcols}
-		i := 0
-		for urow := 0; urow < urows; urow++ {
-			for vcol := 0; vcol < vcols; vcol++ {
-				acc := c.EvalBinary(u.data[urow*ucols], right, v.data[vcol])
-				for vrow := 1; vrow < vrows; vrow++ {
-					acc = c.EvalBinary(acc, left, c.EvalBinary(u.data[urow*ucols+vrow], right, v.data[vrow*vcols+vcol]))
-				}
-				data[i] = acc
-				i++
+	ushape, udata := shapeAndData(u)
+	vshape, vdata := shapeAndData(v)
+	n := ushape[len(ushape)-1]
+	if n != vshape[0] {
+		Errorf("inner product: last axis of left (%d) not equal to first axis of right (%d)", n, vshape[0])
+	}
+	if n == 0 {
+		Errorf("inner product: empty axis")
+	}
+	shape := append(append([]int{}, ushape[:len(ushape)-1]...), vshape[1:]...)
+	rows := len(udata) / n
+	cols := len(vdata) / n
+	data := make(Vector, rows*cols)
+	i := 0
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			acc := c.EvalBinary(udata[row*n], right, vdata[col])
+			for k := 1; k < n; k++ {
+				acc = c.EvalBinary(acc, left, c.EvalBinary(udata[row*n+k], right, vdata[k*cols+col]))
 			}
+			data[i] = acc
+			i++
 		}
-		return NewMatrix(shape, data)
 	}
-	Errorf("can't do inner product on %s", whichType(u))
-	panic("not reached")
+	if len(shape) == 0 {
+		return data[0]
+	}
+	for i, x := range data {
+		data[i] = productItem(c, left, x)
+	}
+	if len(shape) == 1 {
+		return data
+	}
+	return NewMatrix(shape, data)
+}
+
+// productItem returns x, an element of the result of a product using the
+// operator op. If op is user-defined, an array x is enclosed so the
+// result is a well-formed array of boxes. Built-in operators keep their
+// historical behavior, as in the deck of cards made by "A23" o., "♠♡".
+func productItem(c Context, op string, x Value) Value {
+	if c.UserDefined(op, true) {
+		return enclose(c, x)
+	}
+	return x
 }
 
 // outer product computes an outer product such as "o.*".
-// u and v are known to be at least Vectors.
+// u and v are known to be at least Vectors. The operator may be
+// user-defined; if it yields an array, the element is enclosed.
 func outerProduct(c Context, u Value, op string, v Value) Value {
 	switch u := u.(type) {
 	case Vector:
@@ -192,7 +235,7 @@ func outerProduct(c Context, u Value, op string, v Value) Value {
 		index := 0
 		for _, vu := range u {
 			for _, vv := range v {
-				m.data[index] = c.EvalBinary(vu, op, vv)
+				m.data[index] = productItem(c, op, c.EvalBinary(vu, op, vv))
 				index++
 			}
 		}
@@ -206,7 +249,7 @@ func outerProduct(c Context, u Value, op string, v Value) Value {
 		index := 0
 		for _, vu := range u.Data() {
 			for _, vv := range v.Data() {
-				m.data[index] = c.EvalBinary(vu, op, vv)
+				m.data[index] = productItem(c, op, c.EvalBinary(vu, op, vv))
 				index++
 			}
 		}
@@ -216,6 +259,15 @@ func outerProduct(c Context, u Value, op string, v Value) Value {
 	panic("not reached")
 }
 
+// reduceVector reduces the non-empty vector v by op.
+func reduceVector(c Context, op string, v Vector) Value {
+	acc := v[len(v)-1]
+	for i := len(v) - 2; i >= 0; i-- {
+		acc = c.EvalBinary(v[i], op, acc)
+	}
+	return acc
+}
+
 // Reduce computes a reduction such as +/. The slash has been removed.
 func Reduce(c Context, op string, v Value) Value {
 	// We must be right associative; that is the grammar.
@@ -223,15 +275,16 @@ func Reduce(c Context, op string, v Value) Value {
 	switch v := v.(type) {
 	case Int, BigInt, BigRat:
 		return v
+	case Range:
+		return reduceRange(c, op, v)
 	case Vector:
 		if len(v) == 0 {
 			return v
 		}
-		acc := v[len(v)-1]
-		for i := len(v) - 2; i >= 0; i-- {
-			acc = c.EvalBinary(v[i], op, acc)
+		if canReduceInParallel(c, op, v) {
+			return parallelReduce(c, op, v)
 		}
-		return acc
+		return reduceVector(c, op, v)
 	case *Matrix:
 		if v.Rank() < 2 {
 			Errorf("shape for matrix is degenerate: %s", NewIntVector(v.shape))
@@ -267,7 +320,7 @@ func Reduce(c Context, op string, v Value) Value {
 // It gives the successive values of reducing op through v.
 // We must be right associative; that is the grammar.
 func Scan(c Context, op string, v Value) Value {
-	switch v := v.(type) {
+	switch v := Materialize(c.Config(), v).(type) {
 	case Int, BigInt, BigRat:
 		return v
 	case Vector:
@@ -312,6 +365,91 @@ func Scan(c Context, op string, v Value) Value {
 	panic("not reached")
 }
 
+// ReduceFirst computes a reduction such as +/% along the first axis
+// of v. The /% has been removed. For vectors and scalars it is the
+// same as Reduce.
+func ReduceFirst(c Context, op string, v Value) Value {
+	m, ok := v.(*Matrix)
+	if !ok {
+		return Reduce(c, op, v)
+	}
+	columns := m.firstAxis()
+	data := make(Vector, len(columns))
+	for i, col := range columns {
+		data[i] = Reduce(c, op, col)
+	}
+	shape := m.shape[1:]
+	if len(shape) == 1 {
+		return NewVector(data)
+	}
+	return NewMatrix(shape, data)
+}
+
+// NwiseReduce computes an n-wise reduction such as 2 +/ v, which
+// reduces each window of n adjacent elements along the last axis of v.
+// The slash has been removed. If n is negative, each window is reversed
+// before it is reduced.
+func NwiseReduce(c Context, u Value, op string, v Value) Value {
+	u, v = Materialize(c.Config(), u), Materialize(c.Config(), v)
+	if vec, ok := u.(Vector); ok && len(vec) == 1 {
+		u = vec[0]
+	}
+	n, ok := u.(Int)
+	if !ok {
+		Errorf("%s/: window size must be small integer", op)
+	}
+	reverse := n < 0
+	if reverse {
+		n = -n
+	}
+	if v.Rank() == 0 {
+		v = NewVector([]Value{v})
+	}
+	shape, data := shapeAndData(v)
+	length := shape[len(shape)-1]
+	if n == 0 || int(n) > length+1 {
+		Errorf("%s/: window size %d invalid for length %d", op, n, length)
+	}
+	count := length - int(n) + 1
+	result := Vector{}
+	window := make(Vector, n)
+	for base := 0; base < len(data); base += length {
+		for i := 0; i < count; i++ {
+			copy(window, data[base+i:base+i+int(n)])
+			if reverse {
+				for j, k := 0, len(window)-1; j < k; j, k = j+1, k-1 {
+					window[j], window[k] = window[k], window[j]
+				}
+			}
+			result = append(result, Reduce(c, op, window))
+		}
+	}
+	if len(shape) == 1 {
+		return result
+	}
+	newShape := append([]int{}, shape...)
+	newShape[len(shape)-1] = count
+	return NewMatrix(newShape, result)
+}
+
+// ScanFirst computes a scan such as +\% along the first axis of v.
+// The \% has been removed. For vectors and scalars it is the
+// same as Scan.
+func ScanFirst(c Context, op string, v Value) Value {
+	m, ok := v.(*Matrix)
+	if !ok {
+		return Scan(c, op, v)
+	}
+	stride := len(m.data) / m.shape[0]
+	data := make(Vector, len(m.data))
+	for j, col := range m.firstAxis() {
+		for i, x := range Scan(c, op, col).(Vector) {
+			data[i*stride+j] = x
+		}
+	}
+	return NewMatrix(m.shape, data)
+}
+
 // unaryVectorOp applies op elementwise to i.
 func unaryVectorOp(c Context, op string, i Value) Value {
 	u := i.(Vector)
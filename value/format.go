@@ -25,6 +25,9 @@ import (
 // for instance to print a floating point number as a decimal
 // integer with '%d'.
 func fmtText(c Context, u, v Value) Value {
+	if t, ok := v.(Time); ok {
+		return formatTime(c, u, t)
+	}
 	config := c.Config()
 	format, verb := formatString(config, u)
 	if format == "" {
@@ -32,7 +35,7 @@ func fmtText(c Context, u, v Value) Value {
 	}
 	var b bytes.Buffer
 	switch val := v.(type) {
-	case Int, BigInt, BigRat, BigFloat, Char:
+	case Int, BigInt, BigRat, BigFloat, Char, Complex:
 		formatOne(c, &b, format, verb, val)
 	case Vector:
 		if val.AllChars() && strings.ContainsRune("boOqsvxX", rune(verb)) {
@@ -145,6 +148,12 @@ Loop:
 // How it does this depends on the format, permitting us to use %d on
 // floats and rationals, for example.
 func formatOne(c Context, w io.Writer, format string, verb byte, v Value) {
+	if z, ok := v.(Complex); ok {
+		formatOne(c, w, format, verb, z.real)
+		fmt.Fprint(w, "j")
+		formatOne(c, w, format, verb, z.imag)
+		return
+	}
 	switch verb {
 	case 't': // Boolean. TODO: Should be 0 or 1, but that's messy. Odd case anyway.
 		fmt.Fprintf(w, format, toBool(v))
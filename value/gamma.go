@@ -0,0 +1,185 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+func gamma(c Context, v Value) Value {
+	if i, ok := v.(Int); ok {
+		if i <= 0 {
+			Errorf("gamma of non-positive integer %d", i)
+		}
+		return BigInt{factorial(int64(i) - 1)}.shrink()
+	}
+	return evalFloatFunc(c, v, floatGamma)
+}
+
+func lgamma(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatLgamma)
+}
+
+// floatGamma computes Γ(x). For x > 0 it is exp(log Γ(x)); for negative
+// x it uses the reflection formula Γ(x) = π / (sin(πx) Γ(1-x)).
+func floatGamma(c Context, x *big.Float) *big.Float {
+	checkGammaPole(x)
+	if x.IsInt() && x.Sign() > 0 && x.Cmp(newFloat(c).SetInt64(1000)) <= 0 {
+		// Exact.
+		n, _ := x.Int64()
+		return newFloat(c).SetInt(factorial(n - 1))
+	}
+	if x.Sign() > 0 {
+		return exponential(c.Config(), lgammaPositive(c, x))
+	}
+	s := newFloat(c).Mul(floatPi, x)
+	s = floatSin(c, s)
+	g := newFloat(c).Sub(floatOne, x)
+	g = exponential(c.Config(), lgammaPositive(c, g))
+	s.Mul(s, g)
+	return s.Quo(floatPi, s)
+}
+
+// floatLgamma computes log |Γ(x)|.
+func floatLgamma(c Context, x *big.Float) *big.Float {
+	checkGammaPole(x)
+	if x.Sign() > 0 {
+		return lgammaPositive(c, x)
+	}
+	// log |Γ(x)| = log(π / |sin(πx)|) - log Γ(1-x).
+	s := newFloat(c).Mul(floatPi, x)
+	s = floatSin(c, s)
+	s.Abs(s)
+	s.Quo(floatPi, s)
+	z := floatLog(c, s)
+	g := newFloat(c).Sub(floatOne, x)
+	return z.Sub(z, lgammaPositive(c, g))
+}
+
+// checkGammaPole errors out if x is one of the poles of Γ.
+func checkGammaPole(x *big.Float) {
+	if x.IsInt() && x.Sign() <= 0 {
+		Errorf("gamma of non-positive integer %s", BigFloat{x}.Sprint(debugConf))
+	}
+}
+
+// lgammaPositive computes log Γ(x) for x > 0 using Stirling's series,
+//	log Γ(z) = (z-½) log z - z + ½ log 2π + Σ B₂ₖ / (2k(2k-1) z²ᵏ⁻¹)
+// The series is asymptotic, so we first use Γ(x+1) = x Γ(x) to shift the
+// argument up until the terms shrink quickly enough for the precision.
+func lgammaPositive(c Context, x *big.Float) *big.Float {
+	conf := c.Config()
+	min := newFloat(c).SetInt64(int64(conf.FloatPrec()/8 + 10))
+	z := newFloat(c).Set(x)
+	prod := newFloat(c).SetInt64(1)
+	for z.Cmp(min) < 0 {
+		prod.Mul(prod, z)
+		z.Add(z, floatOne)
+	}
+
+	// (z-½) log z - z + ½ log 2π
+	logZ := floatLog(c, z)
+	result := newFloat(c).Sub(z, floatHalf)
+	result.Mul(result, logZ)
+	result.Sub(result, z)
+	twoPi := newFloat(c).Mul(floatPi, floatTwo)
+	halfLog2Pi := floatLog(c, twoPi)
+	halfLog2Pi.Mul(halfLog2Pi, floatHalf)
+	result.Add(result, halfLog2Pi)
+
+	// The sum. zPower holds z²ᵏ⁻¹.
+	zPower := newFloat(c).Set(z)
+	zSquared := newFloat(c).Mul(z, z)
+	term := newFloat(c)
+	den := newFloat(c)
+	for loop := newLoop(conf, "lgamma", x, 1); ; {
+		k := int(loop.i + 1)
+		term.SetRat(bernoulli(2 * k))
+		den.SetInt64(int64(2 * k * (2*k - 1)))
+		den.Mul(den, zPower)
+		term.Quo(term, den)
+		result.Add(result, term)
+		if loop.done(result) {
+			break
+		}
+		zPower.Mul(zPower, zSquared)
+	}
+
+	// Undo the shift: log Γ(x) = log Γ(z) - log(x(x+1)...(z-1)).
+	return result.Sub(result, floatLog(c, prod))
+}
+
+// bernoulliNumbers caches the Bernoulli numbers computed so far.
+var bernoulliNumbers = []*big.Rat{big.NewRat(1, 1)}
+
+// bernoulli returns the Bernoulli number Bₙ, computed with the recurrence
+//	Bₘ = -1/(m+1) Σₖ₌₀..ₘ₋₁ C(m+1, k) Bₖ
+func bernoulli(n int) *big.Rat {
+	for m := len(bernoulliNumbers); m <= n; m++ {
+		sum := new(big.Rat)
+		binom := big.NewInt(1) // C(m+1, k)
+		term := new(big.Rat)
+		for k := 0; k < m; k++ {
+			term.SetInt(binom)
+			term.Mul(term, bernoulliNumbers[k])
+			sum.Add(sum, term)
+			binom.Mul(binom, big.NewInt(int64(m+1-k)))
+			binom.Quo(binom, big.NewInt(int64(k+1)))
+		}
+		sum.Quo(sum, big.NewRat(int64(-(m+1)), 1))
+		bernoulliNumbers = append(bernoulliNumbers, sum)
+	}
+	return bernoulliNumbers[n]
+}
+
+// binomial returns u!v, the number of combinations of v things taken u
+// at a time, for integer u and v. It is zero if u is negative or
+// greater than v.
+func binomial(c Context, u, v Value) Value {
+	k := bigIntOf("!", u)
+	n := bigIntOf("!", v)
+	if k.Sign() < 0 || k.Cmp(n) > 0 {
+		return zero
+	}
+	// C(n, k) == C(n, n-k); use the smaller.
+	nk := new(big.Int).Sub(n, k)
+	if nk.Cmp(k) < 0 {
+		k = nk
+	}
+	if !k.IsInt64() {
+		Errorf("!: result too large")
+	}
+	mustFit(c.Config(), k.Int64()*int64(n.BitLen()))
+	// After step i, z is C(n-k+i, i), so the division is exact.
+	z := big.NewInt(1)
+	m := new(big.Int).Sub(n, k)
+	d := new(big.Int)
+	for i := int64(1); i <= k.Int64(); i++ {
+		m.Add(m, bigOne.Int)
+		z.Mul(z, m)
+		z.Quo(z, d.SetInt64(i))
+	}
+	return BigInt{z}.shrink()
+}
+
+// floatBinomial returns u!v for non-integer u or v, using the
+// generalization Γ(v+1) / (Γ(u+1) Γ(v-u+1)).
+func floatBinomial(c Context, u, v Value) Value {
+	k := floatSelf(c, u).(BigFloat).Float
+	n := floatSelf(c, v).(BigFloat).Float
+	n1 := newFloat(c).Add(n, floatOne)
+	if n1.IsInt() && n1.Sign() <= 0 {
+		Errorf("!: binomial of negative integer %s", BigFloat{n}.Sprint(debugConf))
+	}
+	z := floatGamma(c, n1)
+	k1 := newFloat(c).Add(k, floatOne)
+	nk1 := newFloat(c).Sub(n1, k)
+	for _, x := range []*big.Float{k1, nk1} {
+		if x.IsInt() && x.Sign() <= 0 {
+			// 1/Γ(x) is zero at the poles.
+			return zero
+		}
+		z.Quo(z, floatGamma(c, x))
+	}
+	return BigFloat{z}.shrink()
+}
@@ -6,7 +6,9 @@ package value
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"robpike.io/ivy/config"
 )
@@ -34,21 +36,53 @@ func (i Int) String() string {
 }
 
 func (i Int) Sprint(conf *config.Config) string {
-	format := conf.Format()
+	return i.sprint(conf, conf.FormatFor("int"))
+}
+
+// sprint is Sprint using the given format rather than the configured one.
+func (i Int) sprint(conf *config.Config, format string) string {
 	if format != "" {
-		verb, prec, ok := conf.FloatFormat()
+		verb, prec, ok := conf.FloatFormatFor("int")
 		if ok {
 			return i.floatString(verb, prec)
 		}
 		return fmt.Sprintf(format, int64(i))
 	}
 	base := conf.OutputBase()
-	if base == 0 {
-		base = 10
+	if base == 0 || base == 10 {
+		return group(conf, strconv.FormatInt(int64(i), 10))
 	}
 	return strconv.FormatInt(int64(i), base)
 }
 
+// group inserts the configured grouping separator, if any, between
+// groups of digits in the decimal integer s.
+func group(conf *config.Config, s string) string {
+	sep, size := conf.Grouping()
+	if sep == "" || size <= 0 {
+		return s
+	}
+	sign := ""
+	if s != "" && s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= size {
+		return sign + s
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	first := len(s) % size
+	if first == 0 {
+		first = size
+	}
+	b.WriteString(s[:first])
+	for i := first; i < len(s); i += size {
+		b.WriteString(sep)
+		b.WriteString(s[i : i+size])
+	}
+	return b.String()
+}
+
 func (i Int) ProgString() string {
 	return strconv.FormatInt(int64(i), 10)
 }
@@ -146,6 +180,8 @@ func (i Int) toType(conf *config.Config, which valueType) Value {
 		return bigRatInt64(int64(i))
 	case bigFloatType:
 		return bigFloatInt64(conf, int64(i))
+	case complexType:
+		return Complex{i, zero}
 	case vectorType:
 		return NewVector([]Value{i})
 	case matrixType:
@@ -155,13 +191,47 @@ func (i Int) toType(conf *config.Config, which valueType) Value {
 	return nil
 }
 
+// wrap returns the integer x reduced, if )intbits is set, to the range
+// of a two's-complement integer of that many bits. The dispatchers
+// apply it to the result of every elementwise operator.
+func wrap(c Context, x Value) Value {
+	bits := c.Config().IntBits()
+	if bits == 0 {
+		return x
+	}
+	var z *big.Int
+	switch x := x.(type) {
+	case Int:
+		z = big.NewInt(int64(x))
+	case BigInt:
+		z = new(big.Int).Set(x.Int)
+	default:
+		return x
+	}
+	mod := new(big.Int).Lsh(big.NewInt(1), bits)
+	z.Mod(z, mod)
+	if z.Bit(int(bits)-1) == 1 {
+		z.Sub(z, mod)
+	}
+	return BigInt{z}.shrink()
+}
+
 func (i Int) ToBool() bool {
 	return i != 0
 }
 
-func (i Int) maybeBig() Value {
+// maybeBig returns i, the result of u op v, as an Int if it fits, and
+// otherwise as a BigInt, printing a warning if )warnoverflow is on.
+// If )intbits is set, it instead wraps i around to that many bits.
+func (i Int) maybeBig(c Context, u Int, op string, v Int) Value {
+	if c.Config().IntBits() != 0 {
+		return wrap(c, bigInt64(int64(i)))
+	}
 	if minInt <= i && i <= maxInt {
 		return i
 	}
+	if conf := c.Config(); conf.WarnOverflow() {
+		fmt.Fprintf(conf.ErrOutput(), "overflow: %d %s %d promoted to big int\n", u, op, v)
+	}
 	return bigInt64(int64(i))
 }
@@ -0,0 +1,205 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// JSON. The unary json returns the JSON text of a value and unjson
+// parses JSON text into a value. Numbers map to numbers, strings to
+// char vectors, vectors and matrices to arrays (nested for each axis of
+// a matrix), and boxes to their contents.
+
+// toJSON returns the JSON text of v as a char vector.
+func toJSON(c Context, v Value) Value {
+	var b bytes.Buffer
+	writeJSON(c, &b, v)
+	return newString(b.String())
+}
+
+// writeJSON writes the JSON text of v to b.
+func writeJSON(c Context, b *bytes.Buffer, v Value) {
+	switch v := v.(type) {
+	case Int:
+		b.WriteString(strconv.FormatInt(int64(v), 10))
+	case BigInt:
+		b.WriteString(v.Int.String())
+	case BigRat:
+		writeJSON(c, b, v.toType(c.Config(), bigFloatType))
+	case BigFloat:
+		if isNaN(v) || v.IsInf() {
+			Errorf("json: cannot encode %s", v.Sprint(debugConf))
+		}
+		b.WriteString(v.Text('g', -1))
+	case Char:
+		writeJSONString(b, string(v))
+	case Box:
+		writeJSON(c, b, v.value)
+	case Vector:
+		if len(v) > 0 && v.AllChars() {
+			writeJSONString(b, v.makeString(debugConf, false))
+			return
+		}
+		b.WriteByte('[')
+		for i, x := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSON(c, b, x)
+		}
+		b.WriteByte(']')
+	case *Matrix:
+		if v.shape[0] == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteByte('[')
+		size := len(v.data) / v.shape[0]
+		for i := 0; i < v.shape[0]; i++ {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			row := v.data[i*size : (i+1)*size]
+			if len(v.shape) == 2 {
+				writeJSON(c, b, row)
+			} else {
+				writeJSON(c, b, NewMatrix(v.shape[1:], row))
+			}
+		}
+		b.WriteByte(']')
+	default:
+		Errorf("json: cannot encode %s", v.Sprint(debugConf))
+	}
+}
+
+// writeJSONString writes s to b as a JSON string.
+func writeJSONString(b *bytes.Buffer, s string) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	b.Truncate(b.Len() - 1) // Encode adds a newline.
+}
+
+// fromJSON parses the JSON text v, a char vector, into a value.
+// Integers become integers and other numbers floats. A string of one
+// char becomes a char. An array of arrays of the same shape becomes a
+// matrix; otherwise an array becomes a vector, with strings and arrays
+// in it enclosed. True and false become 1 and 0. Since a string is not
+// an array, the JSON of a char matrix, an array of strings, comes back
+// as a vector of enclosed strings.
+func fromJSON(c Context, v Value) Value {
+	dec := json.NewDecoder(strings.NewReader(goString("unjson", v)))
+	dec.UseNumber()
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		Errorf("unjson: %s", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		Errorf("unjson: extra text after value")
+	}
+	return jsonValue(c, x)
+}
+
+// jsonValue returns the value of the decoded JSON x.
+func jsonValue(c Context, x interface{}) Value {
+	switch x := x.(type) {
+	case json.Number:
+		if i, ok := new(big.Int).SetString(string(x), 10); ok {
+			return BigInt{i}.shrink()
+		}
+		f, _, err := big.ParseFloat(string(x), 10, c.Config().FloatPrec(), big.ToNearestEven)
+		if err != nil {
+			Errorf("unjson: %s", err)
+		}
+		return BigFloat{f}
+	case string:
+		s := newString(x)
+		if len(s) == 1 {
+			return s[0]
+		}
+		return s
+	case bool:
+		if x {
+			return one
+		}
+		return zero
+	case []interface{}:
+		return jsonArray(c, x)
+	case nil:
+		Errorf("unjson: null is not supported")
+	default:
+		Errorf("unjson: objects are not supported")
+	}
+	return nil
+}
+
+// jsonArray returns the value of the decoded JSON array x.
+func jsonArray(c Context, x []interface{}) Value {
+	elems := make([]Value, len(x))
+	for i, e := range x {
+		elems[i] = jsonValue(c, e)
+	}
+	if m := jsonMatrix(x, elems); m != nil {
+		return m
+	}
+	for i, e := range elems {
+		switch e.(type) {
+		case Vector, *Matrix:
+			elems[i] = Box{e}
+		}
+	}
+	return NewVector(elems)
+}
+
+// jsonMatrix returns the matrix whose items are elems, the values of
+// the elements of the JSON array x, or nil if they are not all
+// non-empty arrays of the same shape.
+func jsonMatrix(x []interface{}, elems []Value) *Matrix {
+	var shape []int
+	for i, e := range elems {
+		if _, ok := x[i].([]interface{}); !ok {
+			return nil
+		}
+		var s []int
+		switch e := e.(type) {
+		case Vector:
+			if len(e) == 0 || e.hasBox() {
+				return nil
+			}
+			s = []int{len(e)}
+		case *Matrix:
+			s = e.shape
+		default:
+			return nil
+		}
+		if i > 0 && !sameShape(shape, s) {
+			return nil
+		}
+		shape = s
+	}
+	if shape == nil {
+		return nil
+	}
+	size := 1
+	for _, n := range shape {
+		size *= n
+	}
+	data := make([]Value, 0, len(elems)*size)
+	for _, e := range elems {
+		switch e := e.(type) {
+		case Vector:
+			data = append(data, e...)
+		case *Matrix:
+			data = append(data, e.data...)
+		}
+	}
+	return NewMatrix(append([]int{len(elems)}, shape...), data)
+}
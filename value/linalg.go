@@ -0,0 +1,247 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+// Linear algebra on matrices. The algorithms use the generic arithmetic
+// operators, so they are exact for integer and rational matrices and
+// use the configured precision for floating-point ones.
+
+// rows returns the elements of the square matrix m as a slice of rows,
+// which may be modified without affecting m. The name is used in the
+// error message if m is not square.
+func (m *Matrix) rows(name string) [][]Value {
+	if m.Rank() != 2 || m.shape[0] != m.shape[1] {
+		Errorf("%s requires square matrix", name)
+	}
+	n := m.shape[0]
+	rows := make([][]Value, n)
+	for i := range rows {
+		rows[i] = make([]Value, n)
+		copy(rows[i], m.data[i*n:(i+1)*n])
+	}
+	return rows
+}
+
+// pivot returns the index of the row at or below row k whose
+// element in column k has the largest magnitude, or -1 if
+// all those elements are zero.
+func pivot(c Context, a [][]Value, k int) int {
+	p := -1
+	var max Value = zero
+	for i := k; i < len(a); i++ {
+		mag := c.EvalUnary("abs", a[i][k])
+		if toBool(c.EvalBinary(mag, ">", max)) {
+			p, max = i, mag
+		}
+	}
+	return p
+}
+
+// eliminate subtracts multiples of row k of a from each row i
+// in rows, zeroing their elements in column k. Only the columns
+// from k onwards are updated.
+func eliminate(c Context, a [][]Value, k int, rows []int) {
+	for _, i := range rows {
+		if i == k || !toBool(a[i][k]) {
+			continue
+		}
+		f := c.EvalBinary(a[i][k], "/", a[k][k])
+		for j := k; j < len(a[i]); j++ {
+			a[i][j] = c.EvalBinary(a[i][j], "-", c.EvalBinary(f, "*", a[k][j]))
+		}
+	}
+}
+
+// det returns the determinant of m, computed by Gaussian elimination
+// with partial pivoting.
+func (m *Matrix) det(c Context) Value {
+	a := m.rows("determinant")
+	var det Value = one
+	for k := range a {
+		p := pivot(c, a, k)
+		if p < 0 {
+			return zero
+		}
+		if p != k {
+			a[p], a[k] = a[k], a[p]
+			det = c.EvalUnary("-", det)
+		}
+		det = c.EvalBinary(det, "*", a[k][k])
+		below := make([]int, 0, len(a)-k-1)
+		for i := k + 1; i < len(a); i++ {
+			below = append(below, i)
+		}
+		eliminate(c, a, k, below)
+	}
+	return det
+}
+
+// gaussJordan reduces the square part of the n by n+k matrix a to the
+// identity by Gauss-Jordan elimination with partial pivoting, leaving
+// the solution in the remaining k columns. It reports an error if the
+// square part is singular.
+func gaussJordan(c Context, a [][]Value) {
+	all := make([]int, len(a))
+	for i := range all {
+		all[i] = i
+	}
+	for k := range a {
+		p := pivot(c, a, k)
+		if p < 0 {
+			Errorf("matrix is singular")
+		}
+		a[p], a[k] = a[k], a[p]
+		pk := a[k][k]
+		for j := k; j < len(a[k]); j++ {
+			a[k][j] = c.EvalBinary(a[k][j], "/", pk)
+		}
+		eliminate(c, a, k, all)
+	}
+}
+
+// inverse returns the inverse of m, computed by Gauss-Jordan elimination.
+func (m *Matrix) inverse(c Context) Value {
+	a := m.rows("inverse")
+	n := len(a)
+	// Augment with the identity matrix.
+	for i, row := range a {
+		a[i] = make([]Value, 2*n)
+		copy(a[i], row)
+		for j := n; j < 2*n; j++ {
+			a[i][j] = zero
+		}
+		a[i][n+i] = one
+	}
+	gaussJordan(c, a)
+	data := make([]Value, 0, n*n)
+	for _, row := range a {
+		data = append(data, row[n:]...)
+	}
+	return NewMatrix([]int{n, n}, data)
+}
+
+// divideScalars is binary inv for scalars: the solution of u x = v.
+func divideScalars(c Context, u, v Value) Value {
+	return c.EvalBinary(v, "/", u)
+}
+
+// solve returns the solution x of the linear system a x = b, where b
+// is a vector or a matrix with as many rows as a. If a has more rows
+// than columns, the system is overdetermined and solve returns the
+// least-squares solution, found by solving the normal equations
+// aᵀa x = aᵀb, which are exact for rational data.
+func solve(c Context, a, b *Matrix) Value {
+	if a.Rank() == 1 {
+		// A vector is a single column.
+		a = NewMatrix([]int{a.shape[0], 1}, a.data)
+	}
+	if a.Rank() != 2 || b.Rank() > 2 {
+		Errorf("inv: rank mismatch")
+	}
+	m, n := a.shape[0], a.shape[1]
+	if m < n {
+		Errorf("inv: system is underdetermined")
+	}
+	if b.shape[0] != m {
+		Errorf("inv: length mismatch")
+	}
+	k := 1
+	if b.Rank() == 2 {
+		k = b.shape[1]
+	}
+	aij := func(i, j int) Value { return a.data[i*n+j] }
+	bij := func(i, j int) Value { return b.data[i*k+j] }
+	lhs, rhs := aij, bij
+	if m > n {
+		// Form the normal equations, which are square.
+		ata := func(i, j int) Value {
+			var sum Value = zero
+			for r := 0; r < m; r++ {
+				sum = c.EvalBinary(sum, "+", c.EvalBinary(aij(r, i), "*", aij(r, j)))
+			}
+			return sum
+		}
+		atb := func(i, j int) Value {
+			var sum Value = zero
+			for r := 0; r < m; r++ {
+				sum = c.EvalBinary(sum, "+", c.EvalBinary(aij(r, i), "*", bij(r, j)))
+			}
+			return sum
+		}
+		lhs, rhs = ata, atb
+	}
+	// Augment the square system with the right-hand side.
+	x := make([][]Value, n)
+	for i := range x {
+		x[i] = make([]Value, n+k)
+		for j := 0; j < n; j++ {
+			x[i][j] = lhs(i, j)
+		}
+		for j := 0; j < k; j++ {
+			x[i][n+j] = rhs(i, j)
+		}
+	}
+	gaussJordan(c, x)
+	data := make([]Value, 0, n*k)
+	for _, row := range x {
+		data = append(data, row[n:]...)
+	}
+	if b.Rank() == 1 {
+		return NewVector(data).shrink()
+	}
+	return NewMatrix([]int{n, k}, data)
+}
+
+// matrixPower returns the square matrix u raised to the integer power
+// v by repeated squaring. The zeroth power is the identity, and a
+// negative power is a power of the inverse.
+func matrixPower(c Context, u, v Value) Value {
+	m, ok := u.(*Matrix)
+	if !ok {
+		Errorf("mpow requires square matrix")
+	}
+	m.rows("mpow")
+	exp, ok := v.(Int)
+	if !ok {
+		Errorf("mpow: exponent must be a small integer")
+	}
+	if exp < 0 {
+		m = m.inverse(c).(*Matrix)
+		exp = -exp
+	}
+	n := m.shape[0]
+	if n == 0 {
+		return m
+	}
+	data := make([]Value, n*n)
+	for i := range data {
+		data[i] = zero
+	}
+	for i := 0; i < n; i++ {
+		data[i*n+i] = one
+	}
+	var result Value = NewMatrix([]int{n, n}, data)
+	var sq Value = m
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 != 0 {
+			result = innerProduct(c, result, "+", "*", sq)
+		}
+		if exp > 1 {
+			sq = innerProduct(c, sq, "+", "*", sq)
+		}
+	}
+	return result
+}
+
+// cross returns the cross product of the 3-vectors u and v.
+func cross(c Context, u, v Vector) Value {
+	if len(u) != 3 || len(v) != 3 {
+		Errorf("cross: operands must have length 3")
+	}
+	term := func(i, j int) Value {
+		return c.EvalBinary(c.EvalBinary(u[i], "*", v[j]), "-", c.EvalBinary(u[j], "*", v[i]))
+	}
+	return NewVector([]Value{term(1, 2), term(2, 0), term(0, 1)})
+}
@@ -10,15 +10,68 @@ func logn(c Context, v Value) Value {
 	return evalFloatFunc(c, v, floatLog)
 }
 
+// logBaseU returns the logarithm of v to the base u. If u and v are
+// exact and v is an integral power of u, the result is that integer
+// rather than a float that may be wrong in the last place.
 func logBaseU(c Context, u, v Value) Value {
-	return c.EvalBinary(logn(c, v), "/", logn(c, u))
+	if !c.Config().NaN() {
+		if !toBool(c.EvalBinary(u, ">", zero)) {
+			Errorf("log: base must be positive")
+		}
+		if toBool(c.EvalBinary(u, "==", one)) {
+			Errorf("log: base must not be 1")
+		}
+	}
+	r := c.EvalBinary(logn(c, v), "/", logn(c, u))
+	ur, vr := exactRat(u), exactRat(v)
+	if ur == nil || vr == nil {
+		return r
+	}
+	k, ok := c.EvalUnary("round", r).(Int)
+	if !ok {
+		return r
+	}
+	// If u**k is v, the sizes must match, so don't compute
+	// a huge power only to find it is wrong.
+	bu := ur.Num().BitLen() + ur.Denom().BitLen() - 2
+	bv := vr.Num().BitLen() + vr.Denom().BitLen()
+	if k > Int(bv/bu) || -k > Int(bv/bu) {
+		return r
+	}
+	if toBool(c.EvalBinary(c.EvalBinary(u, "**", k), "==", v)) {
+		return k
+	}
+	return r
+}
+
+// exactRat returns the value of an integer or rational v as a
+// big.Rat, or nil if v is not exact.
+func exactRat(v Value) *big.Rat {
+	switch v := v.(type) {
+	case Int:
+		return big.NewRat(int64(v), 1)
+	case BigInt:
+		return new(big.Rat).SetInt(v.Int)
+	case BigRat:
+		return v.Rat
+	}
+	return nil
 }
 
 // floatLog computes natural log(x) using the Maclaurin series for log(1-x).
 func floatLog(c Context, x *big.Float) *big.Float {
 	if x.Sign() <= 0 {
+		if c.Config().NaN() {
+			if x.Sign() == 0 {
+				return newFloat(c).SetInf(true)
+			}
+			return floatNaN
+		}
 		Errorf("log of non-positive value")
 	}
+	if x.IsInf() {
+		return x
+	}
 	// Convergence is imperfect at 1, so get it right.
 	if x.Cmp(floatOne) == 0 {
 		return newFloat(c)
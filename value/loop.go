@@ -11,6 +11,7 @@ import (
 )
 
 type loop struct {
+	conf          *config.Config
 	name          string     // The name of the function we are evaluating.
 	i             uint64     // Loop count.
 	maxIterations uint64     // When to give up.
@@ -26,6 +27,7 @@ type loop struct {
 // ignore the precision setting.
 func newLoop(conf *config.Config, name string, x *big.Float, itersPerBit uint) *loop {
 	return &loop{
+		conf:          conf,
 		name:          name,
 		arg:           newF(conf).Set(x),
 		maxIterations: 10 + uint64(itersPerBit*conf.FloatPrec()),
@@ -37,6 +39,7 @@ func newLoop(conf *config.Config, name string, x *big.Float, itersPerBit uint) *
 // done reports whether the loop is done. If it does not converge
 // after the maximum number of iterations, it errors out.
 func (l *loop) done(z *big.Float) bool {
+	CheckInterrupt(l.conf)
 	l.delta.Sub(l.prevZ, z)
 	sign := l.delta.Sign()
 	if sign == 0 {
@@ -60,3 +63,16 @@ func (l *loop) done(z *big.Float) bool {
 	l.prevZ.Set(z)
 	return false
 }
+
+// CheckInterrupt errors out if the evaluation has been interrupted or
+// has run out of time. The execution context calls it each time it
+// applies an operator, and so should loops that may run for a long time
+// within a single operator.
+func CheckInterrupt(conf *config.Config) {
+	if conf.Interrupted() {
+		Errorf("interrupted")
+	}
+	if conf.TimedOut() {
+		Errorf("timed out after %s", conf.Timeout())
+	}
+}
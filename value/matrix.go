@@ -54,18 +54,36 @@ func (m *Matrix) Copy() *Matrix {
 // write2d prints the 2d matrix m into the buffer.
 // value is a slice of already-printed values.
 // The receiver provides only the shape of the matrix.
-func (m *Matrix) write2d(b *bytes.Buffer, value []string, width int) {
+// If lineWidth is positive, rows longer than that are
+// continued on indented lines.
+func (m *Matrix) write2d(b *bytes.Buffer, value []string, width, lineWidth int) {
 	nrows := m.shape[0]
 	ncols := m.shape[1]
+	// Values aligned on their decimal points may have trailing spaces.
+	newline := func() {
+		for b.Len() > 0 && b.Bytes()[b.Len()-1] == ' ' {
+			b.Truncate(b.Len() - 1)
+		}
+		b.WriteByte('\n')
+	}
 	for row := 0; row < nrows; row++ {
 		if row > 0 {
-			b.WriteByte('\n')
+			newline()
 		}
 		index := row * ncols
+		lineLen := 0
 		for col := 0; col < ncols; col++ {
 			if col > 0 {
-				b.WriteByte(' ')
+				if lineWidth > 0 && lineLen+1+width > lineWidth {
+					newline()
+					b.WriteString(wrapIndent)
+					lineLen = len(wrapIndent)
+				} else {
+					b.WriteByte(' ')
+					lineLen++
+				}
 			}
+			lineLen += width
 			s := value[index]
 			pad := width - len(s)
 			for ; pad >= 10; pad -= 10 {
@@ -78,6 +96,65 @@ func (m *Matrix) write2d(b *bytes.Buffer, value []string, width int) {
 			index++
 		}
 	}
+	for b.Len() > 0 && b.Bytes()[b.Len()-1] == ' ' {
+		b.Truncate(b.Len() - 1)
+	}
+}
+
+// alignPoints returns the width of the widest of the printed elements
+// in strs. If they are all decimal numbers and some have a decimal
+// point, it first pads them on the right so their points line up when
+// they are right-aligned; an integer's point is at its end.
+func alignPoints(conf *config.Config, strs []string) int {
+	sep, _ := conf.Grouping()
+	isDecimal := func(s string) bool {
+		s = strings.TrimPrefix(s, "-")
+		if sep != "" {
+			s = strings.ReplaceAll(s, sep, "")
+		}
+		if s == "" {
+			return false
+		}
+		for _, r := range s {
+			if r != '.' && (r < '0' || '9' < r) {
+				return false
+			}
+		}
+		return true
+	}
+	aligned := false
+	for _, s := range strs {
+		if !isDecimal(s) {
+			aligned = false
+			break
+		}
+		if strings.Contains(s, ".") {
+			aligned = true
+		}
+	}
+	if aligned {
+		// The widest fraction, including its point.
+		frac := 0
+		for _, s := range strs {
+			if i := strings.Index(s, "."); i >= 0 && frac < len(s)-i {
+				frac = len(s) - i
+			}
+		}
+		for i, s := range strs {
+			f := 0
+			if j := strings.Index(s, "."); j >= 0 {
+				f = len(s) - j
+			}
+			strs[i] = s + strings.Repeat(" ", frac-f)
+		}
+	}
+	wid := 1
+	for _, s := range strs {
+		if wid < len(s) {
+			wid = len(s)
+		}
+	}
+	return wid
 }
 
 func (m *Matrix) fprintf(c Context, w io.Writer, format string) {
@@ -117,6 +194,15 @@ func (m *Matrix) String() string {
 }
 
 func (m *Matrix) Sprint(conf *config.Config) string {
+	return m.sprint(conf, 0)
+}
+
+// sprint is like Sprint but, if width is positive, wraps rows onto
+// lines of at most width characters where possible.
+func (m *Matrix) sprint(conf *config.Config, width int) string {
+	if m.Rank() > 1 && m.data.hasBox() {
+		return m.boxString(conf)
+	}
 	var b bytes.Buffer
 	switch m.Rank() {
 	case 0:
@@ -141,17 +227,10 @@ func (m *Matrix) Sprint(conf *config.Config) string {
 		}
 		// We print the elements into one big string,
 		// slice that, and then format so they line up.
-		// Will need some rethinking when decimal points
-		// can appear.
 		// Vector.String does what we want for the first part.
 		strs := strings.Split(m.data.makeString(conf, true), " ")
-		wid := 1
-		for _, s := range strs {
-			if wid < len(s) {
-				wid = len(s)
-			}
-		}
-		m.write2d(&b, strs, wid)
+		wid := alignPoints(conf, strs)
+		m.write2d(&b, strs, wid, width)
 	case 3:
 		// If it's all chars, print it without padding or quotes.
 		if m.data.AllChars() {
@@ -169,13 +248,8 @@ func (m *Matrix) Sprint(conf *config.Config) string {
 		}
 		// As for 2d: print the vector elements, compute the
 		// global width, and use that to print each 2d submatrix.
-		strs := strings.Split(m.data.Sprint(conf), " ")
-		wid := 1
-		for _, s := range strs {
-			if wid < len(s) {
-				wid = len(s)
-			}
-		}
+		strs := strings.Split(m.data.makeString(conf, true), " ")
+		wid := alignPoints(conf, strs)
 		n2d := m.shape[0]    // number of 2d submatrices.
 		size := m.ElemSize() // number of elems in each submatrix.
 		start := int64(0)
@@ -187,11 +261,11 @@ func (m *Matrix) Sprint(conf *config.Config) string {
 				shape: m.shape[1:],
 				data:  m.data[start : start+size],
 			}
-			m.write2d(&b, strs[start:start+size], wid)
+			m.write2d(&b, strs[start:start+size], wid, width)
 			start += size
 		}
 	default:
-		return m.higherDim(conf, "[", 0)
+		return m.higherDim(conf, "[", 0, width)
 	}
 	return b.String()
 }
@@ -201,9 +275,9 @@ func (m *Matrix) ProgString() string {
 	panic("matrix.ProgString - cannot happen")
 }
 
-func (m *Matrix) higherDim(conf *config.Config, prefix string, indentation int) string {
+func (m *Matrix) higherDim(conf *config.Config, prefix string, indentation, width int) string {
 	if m.Rank() <= 3 {
-		return indent(indentation, m.Sprint(conf))
+		return indent(indentation, m.sprint(conf, width))
 	}
 	dim := m.shape[0]
 	rest := strings.Repeat(" *", m.Rank()-1)[1:]
@@ -218,7 +292,7 @@ func (m *Matrix) higherDim(conf *config.Config, prefix string, indentation int)
 		}
 		innerPrefix := fmt.Sprintf("%s%d ", prefix, i+conf.Origin())
 		b.WriteString(indent(indentation, "%s%s]:\n", innerPrefix, rest))
-		b.WriteString(inner.higherDim(conf, innerPrefix, indentation+1))
+		b.WriteString(inner.higherDim(conf, innerPrefix, indentation+1, width))
 	}
 	return b.String()
 }
@@ -326,34 +400,51 @@ func (x *Matrix) sameShape(y *Matrix) {
 
 // reshape implements binary rho
 // A⍴B: Array of shape A with data B
+// The data of B is repeated cyclically to fill the result. If B is
+// empty, the result is filled with zeros (spaces for chars) instead.
 func reshape(A, B Vector) Value {
-	if len(B) == 0 {
-		Errorf("reshape of empty vector")
-	}
 	if len(A) == 0 {
 		return Vector{}
 	}
-	nelems := Int(1)
 	shape := make([]int, len(A))
+	empty := false
 	for i := range A {
 		n, ok := A[i].Inner().(Int)
 		if !ok || n < 0 || maxInt < n {
 			Errorf("bad shape for rho: %s is not a small integer", A[i])
 		}
-		nelems *= n
-		if nelems > maxInt {
-			Errorf("rho has too many elements")
-		}
 		shape[i] = int(n)
+		empty = empty || n == 0
+	}
+	// Check the size before computing it, since the product may overflow.
+	nelems := int64(1)
+	if empty {
+		nelems = 0
+	}
+	for _, n := range shape {
+		if empty {
+			break
+		}
+		if nelems > 1e8/int64(n) {
+			Errorf("rho: result too large")
+		}
+		nelems *= int64(n)
 	}
 	values := make([]Value, nelems)
-	j := 0
-	for i := range values {
-		if j >= len(B) {
-			j = 0
+	if len(B) == 0 {
+		fill := fillFor(B)
+		for i := range values {
+			values[i] = fill
+		}
+	} else {
+		j := 0
+		for i := range values {
+			if j >= len(B) {
+				j = 0
+			}
+			values[i] = B[j]
+			j++
 		}
-		values[i] = B[j]
-		j++
 	}
 	if len(A) == 1 {
 		return NewVector(values)
@@ -379,6 +470,738 @@ func (m *Matrix) rotate(n int) Value {
 	return NewMatrix(m.shape, elems)
 }
 
+// take implements u take v. The counts in u apply to the leading axes
+// of v. A positive count takes elements from the start of the axis,
+// a negative one from the end. If the count exceeds the length of the
+// axis, the result is padded with zeros, or spaces if v holds chars.
+func take(c Context, u, v Value) Value {
+	shape, data := shapeAndData(v)
+	counts := takeDropCounts("take", u, len(shape))
+	newShape := make([]int, len(shape))
+	offset := make([]int, len(shape))
+	for k, dim := range shape {
+		n := dim
+		if k < len(counts) {
+			n = counts[k]
+		}
+		if n >= 0 {
+			newShape[k] = n
+		} else {
+			newShape[k] = -n
+			offset[k] = dim + n
+		}
+	}
+	return extract("take", shape, data, newShape, offset)
+}
+
+// drop implements u drop v. The counts in u apply to the leading axes
+// of v. A positive count drops elements from the start of the axis,
+// a negative one from the end. Dropping more than there are leaves none.
+func drop(c Context, u, v Value) Value {
+	shape, data := shapeAndData(v)
+	counts := takeDropCounts("drop", u, len(shape))
+	newShape := make([]int, len(shape))
+	offset := make([]int, len(shape))
+	for k, dim := range shape {
+		n := 0
+		if k < len(counts) {
+			n = counts[k]
+		}
+		switch {
+		case n >= dim || -n >= dim:
+			newShape[k] = 0
+		case n >= 0:
+			newShape[k] = dim - n
+			offset[k] = n
+		default:
+			newShape[k] = dim + n
+		}
+	}
+	return extract("drop", shape, data, newShape, offset)
+}
+
+// firstItem returns the first item of v along its first axis, or the
+// last if last is set: an element of a vector, a row of a matrix. If
+// v is empty, the item is made of fill elements.
+func firstItem(c Context, v Value, last bool) Value {
+	shape, data := shapeAndData(v)
+	itemShape := shape[1:]
+	n := int(size(itemShape))
+	item := make([]Value, n)
+	switch {
+	case shape[0] == 0:
+		fill := fillFor(data)
+		for i := range item {
+			item[i] = fill
+		}
+	case last:
+		copy(item, data[len(data)-n:])
+	default:
+		copy(item, data)
+	}
+	switch len(itemShape) {
+	case 0:
+		return item[0]
+	case 1:
+		return NewVector(item)
+	}
+	return NewMatrix(itemShape, item)
+}
+
+// tail returns v without its first item along the first axis, or
+// without its last if head is set.
+func tail(c Context, v Value, head bool) Value {
+	n := one
+	if head {
+		n = Int(-1)
+	}
+	return drop(c, NewVector([]Value{n}), v)
+}
+
+// grade returns as a Vector the indexes that sort the rows of m, that is
+// its items along the first axis, into lexicographic increasing order, or
+// decreasing order if down is set.
+func (m *Matrix) grade(c Context, down bool) Vector {
+	return grade(c, m.items(), down)
+}
+
+// sorted returns a copy of m with its rows sorted lexicographically into
+// increasing order, or decreasing order if down is set.
+func (m *Matrix) sorted(c Context, down bool) *Matrix {
+	rows := m.items()
+	data := make(Vector, 0, len(m.data))
+	for _, index := range sortedIndexes(c, rows, down) {
+		data = append(data, rows[index].(Vector)...)
+	}
+	return NewMatrix(m.shape, data)
+}
+
+// unique returns the distinct rows of m, in order of first appearance.
+func (m *Matrix) unique(c Context) *Matrix {
+	var rows []Vector
+	data := Vector{}
+Loop:
+	for _, row := range m.items() {
+		row := row.(Vector)
+		for _, r := range rows {
+			if r.equal(c, row) {
+				continue Loop
+			}
+		}
+		rows = append(rows, row)
+		data = append(data, row...)
+	}
+	shape := append([]int{len(rows)}, m.shape[1:]...)
+	return NewMatrix(shape, data)
+}
+
+// where returns the indexes of the non-zero elements of v, a vector or
+// matrix of non-negative integers, with each index repeated as many times
+// as the value of its element. For a vector, the result is a vector; for
+// a matrix, it is a matrix whose rows hold the coordinates of the elements.
+func where(c Context, v Value) Value {
+	shape, data := shapeAndData(v)
+	origin := c.Config().Origin()
+	coords := make([]int, len(shape))
+	var result Vector
+	n := 0
+	for _, x := range data {
+		count, ok := x.(Int)
+		if !ok || count < 0 {
+			Errorf("where: argument must be non-negative small integers")
+		}
+		if n += int(count); n > 1e8 {
+			Errorf("where: result too large")
+		}
+		for ; count > 0; count-- {
+			for _, k := range coords {
+				result = append(result, Int(k+origin))
+			}
+		}
+		// Advance the coordinates, last axis fastest.
+		for k := len(coords) - 1; k >= 0; k-- {
+			if coords[k]++; coords[k] < shape[k] {
+				break
+			}
+			coords[k] = 0
+		}
+	}
+	if len(shape) == 1 {
+		if result == nil {
+			return Vector{}
+		}
+		return result
+	}
+	return NewMatrix([]int{n, len(shape)}, result)
+}
+
+// kron returns the Kronecker product of u and v, the block array in
+// which each element of u is replaced by its product with v. If the
+// ranks differ, the shorter shape is padded with leading 1s, and each
+// axis of the result is the product of the corresponding axes.
+func kron(c Context, u, v *Matrix) *Matrix {
+	ushape, vshape := u.shape, v.shape
+	rank := len(ushape)
+	if len(vshape) > rank {
+		rank = len(vshape)
+	}
+	pad := func(shape []int) []int {
+		p := make([]int, rank)
+		for i := range p {
+			p[i] = 1
+		}
+		copy(p[rank-len(shape):], shape)
+		return p
+	}
+	ushape, vshape = pad(ushape), pad(vshape)
+	shape := make([]int, rank)
+	for i := range shape {
+		shape[i] = ushape[i] * vshape[i]
+	}
+	if len(u.data) > 0 && len(v.data) > 1e8/len(u.data) {
+		Errorf("kron: result too large")
+	}
+	data := make(Vector, len(u.data)*len(v.data))
+	for r := range data {
+		ui, vi := 0, 0
+		ustride, vstride := 1, 1
+		x := r
+		for k := rank - 1; k >= 0; k-- {
+			pos := x % shape[k]
+			x /= shape[k]
+			ui += pos / vshape[k] * ustride
+			vi += pos % vshape[k] * vstride
+			ustride *= ushape[k]
+			vstride *= vshape[k]
+		}
+		data[r] = c.EvalBinary(u.data[ui], "*", v.data[vi])
+	}
+	return NewMatrix(shape, data)
+}
+
+// selectMask implements u select v, choosing elementwise between two
+// branches according to the mask u: where u is 1 the result holds the
+// element of the first branch, where it is 0 that of the second. The
+// branches are the two rows (items) of v, or the contents of v if it is
+// a vector of two boxes. Each branch must have the shape of the mask or
+// be a scalar.
+func selectMask(c Context, u, v Value) Value {
+	mshape, mask := catenateArg(u)
+	var branches [2]Value
+	switch v := v.(type) {
+	case *Matrix:
+		if v.shape[0] != 2 {
+			Errorf("select: right operand must have 2 rows, not %d", v.shape[0])
+		}
+		items := v.items()
+		for i := range branches {
+			branches[i] = items[i]
+			if len(v.shape) > 2 {
+				branches[i] = NewMatrix(v.shape[1:], items[i].(Vector))
+			}
+		}
+	case Vector:
+		if len(v) != 2 {
+			Errorf("select: right operand must be 2 boxes or have 2 rows")
+		}
+		for i, x := range v {
+			if b, ok := x.(Box); ok {
+				x = b.Contents()
+			}
+			branches[i] = x
+		}
+	default:
+		Errorf("select: right operand must be 2 boxes or have 2 rows")
+	}
+	var data [2][]Value
+	for i, b := range branches {
+		shape, d := catenateArg(b)
+		if len(shape) > 0 && !sameShape(shape, mshape) {
+			Errorf("select: branch shape %s does not match mask shape %s", NewIntVector(shape), NewIntVector(mshape))
+		}
+		data[i] = d
+	}
+	result := make([]Value, len(mask))
+	for i, m := range mask {
+		b := 0
+		switch m {
+		case one:
+		case zero:
+			b = 1
+		default:
+			Errorf("select: mask must be 0s and 1s")
+		}
+		if len(data[b]) == 1 {
+			result[i] = data[b][0]
+		} else {
+			result[i] = data[b][i]
+		}
+	}
+	switch len(mshape) {
+	case 0:
+		return result[0]
+	case 1:
+		return NewVector(result)
+	}
+	return NewMatrix(mshape, result)
+}
+
+// sameShape reports whether the shapes a and b are the same.
+func sameShape(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// items returns the items of m along its first axis, each as a Vector.
+func (m *Matrix) items() []Value {
+	rows := make([]Value, m.shape[0])
+	if len(rows) > 0 {
+		size := len(m.data) / len(rows)
+		for i := range rows {
+			rows[i] = m.data[i*size : (i+1)*size]
+		}
+	}
+	return rows
+}
+
+// shapeAndData returns the shape and elements of the vector or matrix v.
+func shapeAndData(v Value) ([]int, []Value) {
+	if m, ok := v.(*Matrix); ok {
+		return m.shape, m.data
+	}
+	x := v.(Vector)
+	return []int{len(x)}, x
+}
+
+// takeDropCounts returns the counts in u for the take or drop operator
+// applied to an array of the given rank. There may be fewer counts than
+// the rank but not more.
+func takeDropCounts(op string, u Value, rank int) []int {
+	var elems []Value
+	switch u := u.(type) {
+	case Vector:
+		elems = u
+	case *Matrix:
+		if u.Rank() != 1 {
+			Errorf("%s: count must be a vector", op)
+		}
+		elems = u.data
+	}
+	if len(elems) == 0 || len(elems) > rank {
+		Errorf("%s: %d counts for rank %d", op, len(elems), rank)
+	}
+	counts := make([]int, len(elems))
+	for i, e := range elems {
+		n, ok := e.(Int)
+		if !ok {
+			Errorf("%s: count must be small integer", op)
+		}
+		counts[i] = int(n)
+	}
+	return counts
+}
+
+// fillFor returns the element used to pad an array with the given data:
+// a space if it holds chars, otherwise zero.
+func fillFor(data []Value) Value {
+	if len(data) > 0 {
+		if _, ok := data[0].(Char); ok {
+			return Char(' ')
+		}
+	}
+	return zero
+}
+
+// extract returns the array of the new shape whose element at index i
+// (in each axis) is the element of the array with the given shape and
+// data at index i+offset, or the fill element if that is out of range.
+func extract(op string, shape []int, data []Value, newShape, offset []int) Value {
+	size := int64(1)
+	for _, n := range newShape {
+		size *= int64(n)
+		if size > 1e8 {
+			Errorf("%s: result too large", op)
+		}
+	}
+	fill := fillFor(data)
+	rank := len(shape)
+	stride := make([]int, rank)
+	s := 1
+	for k := rank - 1; k >= 0; k-- {
+		stride[k] = s
+		s *= shape[k]
+	}
+	result := make([]Value, size)
+	index := make([]int, rank)
+	for i := range result {
+		src := 0
+		for k, x := range index {
+			j := x + offset[k]
+			if j < 0 || j >= shape[k] {
+				src = -1
+				break
+			}
+			src += j * stride[k]
+		}
+		if src < 0 {
+			result[i] = fill
+		} else {
+			result[i] = data[src]
+		}
+		// Step to the next index.
+		for k := rank - 1; k >= 0; k-- {
+			index[k]++
+			if index[k] < newShape[k] {
+				break
+			}
+			index[k] = 0
+		}
+	}
+	if rank == 1 {
+		return NewVector(result)
+	}
+	return NewMatrix(newShape, result)
+}
+
+// Reverse implements op[axis] v, where op is rot or flip, which
+// reverses v along the axis, counted from the index origin.
+func Reverse(c Context, op string, v, axis Value) Value {
+	k := axisOf(c, op, v, axis)
+	m, ok := v.(*Matrix)
+	if !ok {
+		return c.EvalUnary("rot", v)
+	}
+	return m.rearrangeAxis(k, func(i, dim int) int { return dim - 1 - i })
+}
+
+// Rotate implements u op[axis] v, where op is rot or flip, which
+// rotates v by u positions along the axis, counted from the index
+// origin.
+func Rotate(c Context, op string, u, v, axis Value) Value {
+	k := axisOf(c, op, v, axis)
+	var count Value = u
+	switch u := u.(type) {
+	case Vector:
+		if len(u) == 1 {
+			count = u[0]
+		}
+	case *Matrix:
+		if len(u.data) == 1 {
+			count = u.data[0]
+		}
+	}
+	n, ok := count.(Int)
+	if !ok {
+		Errorf("%s: count must be small integer", op)
+	}
+	m, ok := v.(*Matrix)
+	if !ok {
+		return v.(Vector).rotate(int(n))
+	}
+	return m.rearrangeAxis(k, func(i, dim int) int {
+		j := (i + int(n)) % dim
+		if j < 0 {
+			j += dim
+		}
+		return j
+	})
+}
+
+// Catenate implements u ,[axis] v. If the axis, counted from the index
+// origin, is an integer, u and v are joined along that axis, and must
+// match in shape along the others; either may lack the axis, or be a
+// scalar. If the axis is fractional, they are laminated: joined along
+// a new axis inserted between the axes on either side of it, so that
+// 1 2 3 ,[0.5] 4 5 6 is a 2×3 matrix. They must then have the same
+// shape, unless one is a scalar.
+func Catenate(c Context, u, v, axis Value) Value {
+	ushape, udata := catenateArg(u)
+	vshape, vdata := catenateArg(v)
+	rank := len(ushape)
+	if len(vshape) > rank {
+		rank = len(vshape)
+	}
+	origin := Int(c.Config().Origin())
+	k, ok := axis.(Int)
+	if ok {
+		k -= origin
+		if k < 0 || int(k) >= rank {
+			Errorf(",: axis %d out of range for rank %d", k+origin, rank)
+		}
+	} else {
+		if _, ok := axis.(BigRat); !ok {
+			if _, ok := axis.(BigFloat); !ok {
+				Errorf(",: axis must be a number")
+			}
+		}
+		k, ok = c.EvalUnary("floor", c.EvalBinary(axis, "-", origin)).(Int)
+		k++
+		if !ok || k < 0 || int(k) > rank {
+			Errorf(",: axis %s out of range for rank %d", axis, rank)
+		}
+		// Give each operand a new axis of length 1 and join on that.
+		if len(ushape) == rank {
+			ushape = insertAxis(ushape, int(k))
+		}
+		if len(vshape) == rank {
+			vshape = insertAxis(vshape, int(k))
+		}
+		rank++
+	}
+	// Give an operand lacking the axis an axis of length 1, and
+	// extend a scalar to the shape of the other operand.
+	if len(ushape) == 0 {
+		ushape, udata = extendScalar(vshape, int(k), udata[0])
+	}
+	if len(vshape) == 0 {
+		vshape, vdata = extendScalar(ushape, int(k), vdata[0])
+	}
+	if len(ushape) == rank-1 {
+		ushape = insertAxis(ushape, int(k))
+	}
+	if len(vshape) == rank-1 {
+		vshape = insertAxis(vshape, int(k))
+	}
+	if len(ushape) != len(vshape) {
+		Errorf(",: rank mismatch: %d != %d", len(ushape), len(vshape))
+	}
+	for i := range ushape {
+		if i != int(k) && ushape[i] != vshape[i] {
+			Errorf(",: shape mismatch: %s != %s", NewIntVector(ushape), NewIntVector(vshape))
+		}
+	}
+	shape := append([]int{}, ushape...)
+	shape[k] += vshape[k]
+	inner := int(size(shape[k+1:]))
+	ulen := ushape[k] * inner
+	vlen := vshape[k] * inner
+	data := make(Vector, 0, size(shape))
+	for i := 0; i < int(size(shape[:k])); i++ {
+		data = append(data, udata[i*ulen:(i+1)*ulen]...)
+		data = append(data, vdata[i*vlen:(i+1)*vlen]...)
+	}
+	if len(shape) == 1 {
+		return data
+	}
+	return NewMatrix(shape, data)
+}
+
+// catenateArg returns the shape and data of an operand of Catenate.
+// A scalar has an empty shape.
+func catenateArg(v Value) ([]int, []Value) {
+	switch v.(type) {
+	case Vector, *Matrix:
+		return shapeAndData(v)
+	}
+	return nil, []Value{v}
+}
+
+// insertAxis returns a copy of shape with an axis of length 1
+// inserted at position k.
+func insertAxis(shape []int, k int) []int {
+	s := make([]int, 0, len(shape)+1)
+	s = append(s, shape[:k]...)
+	s = append(s, 1)
+	return append(s, shape[k:]...)
+}
+
+// extendScalar returns the shape and data of an array holding x with
+// the given shape, except that axis k, if present, has length 1.
+func extendScalar(shape []int, k int, x Value) ([]int, []Value) {
+	s := append([]int{}, shape...)
+	if k < len(s) {
+		s[k] = 1
+	}
+	data := make([]Value, size(s))
+	for i := range data {
+		data[i] = x
+	}
+	return s, data
+}
+
+// Replicate implements u sel[axis] v, which replicates each item of v
+// along the axis, counted from the index origin, the number of times
+// given by the corresponding element of u. A negative count inserts
+// that many fill items instead. If axis is nil, the last axis is used.
+func Replicate(c Context, u, v, axis Value) Value {
+	counts, shape, data, k := axisArgs(c, "sel", u, v, axis)
+	dim := shape[k]
+	if len(counts) != 1 && len(counts) != dim {
+		Errorf("sel: unequal lengths %d != %d", len(counts), dim)
+	}
+	var from []int
+	for i := 0; i < dim; i++ {
+		n := counts[0]
+		if len(counts) > 1 {
+			n = counts[i]
+		}
+		src := i
+		if n < 0 {
+			n, src = -n, -1
+		}
+		from = appendRepeated("sel", from, src, n)
+	}
+	return gatherAxis(shape, data, k, from)
+}
+
+// Expand implements u fill[axis] v, also written u \[axis] v. Each
+// positive count in u takes the next item of v along the axis, counted
+// from the index origin, that many times; a zero inserts one fill item
+// and a negative count that many. If axis is nil, the last axis is used.
+func Expand(c Context, op string, u, v, axis Value) Value {
+	counts, shape, data, k := axisArgs(c, op, u, v, axis)
+	var from []int
+	src := 0
+	for _, n := range counts {
+		switch {
+		case n == 0:
+			from = append(from, -1)
+		case n < 0:
+			from = appendRepeated(op, from, -1, -n)
+		default:
+			from = appendRepeated(op, from, src, n)
+			src++
+		}
+	}
+	if src != shape[k] {
+		Errorf("%s: count > 0 on left (%d) must equal length of right (%d)", op, src, shape[k])
+	}
+	return gatherAxis(shape, data, k, from)
+}
+
+// axisArgs unpacks the arguments of u op[axis] v, where u is a vector of
+// counts, returning the counts, the shape and elements of v, and the
+// axis counted from 0. If axis is nil, the last axis is used.
+func axisArgs(c Context, op string, u, v, axis Value) ([]int, []int, []Value, int) {
+	if v.Rank() == 0 {
+		v = NewVector([]Value{v})
+	}
+	shape, data := shapeAndData(v)
+	k := len(shape) - 1
+	if axis != nil {
+		k = axisOf(c, op, v, axis)
+	}
+	var elems []Value
+	switch u := u.(type) {
+	case Vector:
+		elems = u
+	case *Matrix:
+		if u.Rank() != 1 {
+			Errorf("%s: left operand must be a vector", op)
+		}
+		elems = u.data
+	default:
+		elems = []Value{u}
+	}
+	counts := make([]int, len(elems))
+	for i, x := range elems {
+		n, ok := x.(Int)
+		if !ok {
+			Errorf("left operand of %s must be small integers", op)
+		}
+		counts[i] = int(n)
+	}
+	return counts, shape, data, k
+}
+
+// appendRepeated appends n copies of i to from.
+func appendRepeated(op string, from []int, i, n int) []int {
+	if len(from)+n > 1e8 {
+		Errorf("%s: result too large", op)
+	}
+	for ; n > 0; n-- {
+		from = append(from, i)
+	}
+	return from
+}
+
+// gatherAxis returns the array with the given shape and elements
+// rearranged along axis k, so that position i along the axis holds the
+// items from position from[i], or fill items if from[i] is negative.
+func gatherAxis(shape []int, data []Value, k int, from []int) Value {
+	dim := shape[k]
+	inner := 1
+	for _, d := range shape[k+1:] {
+		inner *= d
+	}
+	outer := 1
+	for _, d := range shape[:k] {
+		outer *= d
+	}
+	fill := fillFor(data)
+	result := make([]Value, 0, outer*len(from)*inner)
+	for base := 0; base < outer*dim*inner; base += dim * inner {
+		for _, src := range from {
+			for j := 0; j < inner; j++ {
+				if src < 0 {
+					result = append(result, fill)
+				} else {
+					result = append(result, data[base+src*inner+j])
+				}
+			}
+		}
+	}
+	if len(shape) == 1 {
+		return NewVector(result)
+	}
+	newShape := append([]int{}, shape...)
+	newShape[k] = len(from)
+	return NewMatrix(newShape, result)
+}
+
+// axisOf returns the axis, counted from 0, given by the value for
+// the operator applied to v. It must be a valid axis of v.
+func axisOf(c Context, op string, v, axis Value) int {
+	rank := 0
+	switch v := v.(type) {
+	case Vector:
+		rank = 1
+	case *Matrix:
+		rank = v.Rank()
+	}
+	if a, ok := axis.(Vector); ok && len(a) == 1 {
+		axis = a[0]
+	}
+	k, ok := axis.(Int)
+	if !ok {
+		Errorf("%s: axis must be small integer", op)
+	}
+	origin := c.Config().Origin()
+	if int(k) < origin || int(k) >= origin+rank {
+		Errorf("%s: axis %d out of range for rank %d", op, k, rank)
+	}
+	return int(k) - origin
+}
+
+// rearrangeAxis returns a copy of m with the elements along the axis,
+// counted from 0, rearranged so that position i holds the elements
+// from position from(i, dim) in m, where dim is the length of the axis.
+func (m *Matrix) rearrangeAxis(axis int, from func(i, dim int) int) Value {
+	dim := m.shape[axis]
+	if dim == 0 {
+		return m
+	}
+	inner := 1
+	for _, d := range m.shape[axis+1:] {
+		inner *= d
+	}
+	elems := make([]Value, len(m.data))
+	for base := 0; base < len(elems); base += dim * inner {
+		for i := 0; i < dim; i++ {
+			src := base + from(i, dim)*inner
+			copy(elems[base+i*inner:base+(i+1)*inner], m.data[src:src+inner])
+		}
+	}
+	return NewMatrix(m.shape, elems)
+}
+
 // vrotate returns a copy of v with elements rotated down by n.
 // Rotation occurs on the leftmost axis.
 func (m *Matrix) vrotate(n int) Value {
@@ -443,6 +1266,70 @@ func (m *Matrix) transpose() *Matrix {
 	return NewMatrix(shape, result)
 }
 
+// transposeAxes implements dyadic transp: element k of u, counted from
+// the origin, is the axis of the result to which axis k of v goes. If
+// several axes of v go to the same axis, the result takes their
+// diagonal, so 1 1 transp M is the main diagonal of M.
+func transposeAxes(c Context, u, v Value) Value {
+	ushape, perm := shapeAndData(u)
+	shape, data := shapeAndData(v)
+	rank := len(shape)
+	if len(ushape) != 1 || len(perm) != rank {
+		Errorf("transp: left operand must be a vector of length %d", rank)
+	}
+	origin := c.Config().Origin()
+	axes := make([]int, rank)
+	n := 0
+	for k, x := range perm {
+		a, ok := x.(Int)
+		if !ok {
+			Errorf("transp: axis must be small integer")
+		}
+		if int(a) < origin || int(a) >= origin+rank {
+			Errorf("transp: axis %d out of range for rank %d", a, rank)
+		}
+		axes[k] = int(a) - origin
+		if axes[k] >= n {
+			n = axes[k] + 1
+		}
+	}
+	// Each result axis has the length of the shortest axis going to it.
+	newShape := make([]int, n)
+	for i := range newShape {
+		newShape[i] = -1
+	}
+	for k, a := range axes {
+		if newShape[a] < 0 || shape[k] < newShape[a] {
+			newShape[a] = shape[k]
+		}
+	}
+	for i, d := range newShape {
+		if d < 0 {
+			Errorf("transp: left operand omits axis %d", i+origin)
+		}
+	}
+	result := make(Vector, size(newShape))
+	counters := make([]int, n)
+	indexes := make([]int, rank)
+	for i := range result {
+		for k, a := range axes {
+			indexes[k] = counters[a]
+		}
+		result[i] = data[offset(shape, indexes)]
+		for k := n - 1; k >= 0; k-- {
+			counters[k]++
+			if counters[k] < newShape[k] {
+				break
+			}
+			counters[k] = 0
+		}
+	}
+	if n == 1 {
+		return result
+	}
+	return NewMatrix(newShape, result)
+}
+
 // offset returns, given a matrix's shape, the index within the slice holding the
 // data of the element indexed in the full matrix by the successive indexes.
 func offset(shape, indexes []int) int {
@@ -454,3 +1341,142 @@ func offset(shape, indexes []int) int {
 	}
 	return j
 }
+
+// decode returns the value of the digits B in the mixed radix A,
+// the result of polynomial B at x=A. If A is a vector, the elements
+// of A align with B. A one-element A or B is extended to the length
+// of the other.
+func decode(c Context, A, B []Value) Value {
+	if len(A) == 0 || len(B) == 0 {
+		return Int(0)
+	}
+	if len(A) != 1 && len(B) != 1 && len(A) != len(B) {
+		Errorf("decode of unequal lengths")
+	}
+	result := Value(Int(0))
+	prod := Value(Int(1))
+	get := func(v []Value, i int) Value {
+		if len(v) == 1 {
+			return v[0]
+		}
+		return v[i]
+	}
+	n := len(A)
+	if len(B) > n {
+		n = len(B)
+	}
+	for i := n - 1; i >= 0; i-- {
+		result = c.EvalBinary(result, "+", c.EvalBinary(prod, "*", get(B, i)))
+		prod = c.EvalBinary(prod, "*", get(A, i))
+	}
+	return result
+}
+
+// decodeMatrix is decode for arrays. Each row (last axis) of A is a
+// radix applied to each column (first axis) of B, so the shape of the
+// result is the shape of A without its last axis followed by the
+// shape of B without its first.
+func decodeMatrix(c Context, A, B *Matrix) Value {
+	n := A.shape[len(A.shape)-1]
+	k := B.shape[0]
+	if n == 0 || k == 0 {
+		return Int(0)
+	}
+	m, p := len(A.data)/n, len(B.data)/k
+	shape := append(append([]int{}, A.shape[:len(A.shape)-1]...), B.shape[1:]...)
+	data := make([]Value, m*p)
+	col := make([]Value, k)
+	for j := 0; j < p; j++ {
+		for r := range col {
+			col[r] = B.data[r*p+j]
+		}
+		for i := 0; i < m; i++ {
+			data[i*p+j] = decode(c, A.data[i*n:(i+1)*n], col)
+		}
+	}
+	switch len(shape) {
+	case 0:
+		return data[0]
+	case 1:
+		return NewVector(data)
+	}
+	return NewMatrix(shape, data)
+}
+
+// encodeMod is the residue used by encode: b mod a, or b if a is zero.
+func encodeMod(c Context, b, a Value) Value {
+	if z, ok := a.(Int); ok && z == 0 {
+		return b
+	}
+	return c.EvalBinary(b, "mod", a)
+}
+
+// encodeDiv is the quotient used by encode: b div a, or b if a is zero.
+func encodeDiv(c Context, b, a Value) Value {
+	if z, ok := a.(Int); ok && z == 0 {
+		return b
+	}
+	return c.EvalBinary(b, "div", a)
+}
+
+// encode returns the digits of b in the mixed radix A.
+// If they are negative the answers disagree with APL because
+// of how modulo arithmetic works.
+func encode(c Context, A []Value, b Value) []Value {
+	elems := make([]Value, len(A))
+	for i := len(A) - 1; i >= 0; i-- {
+		a := A[i]
+		elems[i] = encodeMod(c, b, a)
+		b = encodeDiv(c, b, a)
+	}
+	return elems
+}
+
+// encodeMatrix is encode for arrays. Each column (first axis) of A is
+// a radix in which each element of B is encoded, so the shape of the
+// result is the shape of A followed by the shape of B.
+func encodeMatrix(c Context, A, B *Matrix) Value {
+	shape := append([]int{}, A.shape...)
+	if B.Rank() > 1 || len(B.data) != 1 {
+		shape = append(shape, B.shape...)
+	}
+	n := A.shape[0]
+	m, p := 0, len(B.data)
+	if n > 0 {
+		m = len(A.data) / n
+	}
+	data := make([]Value, n*m*p)
+	radix := make([]Value, n)
+	for j := 0; j < m; j++ {
+		for i := range radix {
+			radix[i] = A.data[i*m+j]
+		}
+		for k, b := range B.data {
+			for i, d := range encode(c, radix, b) {
+				data[(i*m+j)*p+k] = d
+			}
+		}
+	}
+	if len(shape) == 1 {
+		return NewVector(data)
+	}
+	return NewMatrix(shape, data)
+}
+
+// firstAxis returns the vectors of elements of m that run along its
+// first axis, one for each position in the remaining axes.
+func (m *Matrix) firstAxis() []Vector {
+	if m.Rank() < 2 || m.shape[0] == 0 {
+		Errorf("shape for matrix is degenerate: %s", NewIntVector(m.shape))
+	}
+	n := m.shape[0]
+	stride := len(m.data) / n
+	vecs := make([]Vector, stride)
+	for j := range vecs {
+		vecs[j] = make(Vector, n)
+		for i := range vecs[j] {
+			vecs[j][i] = m.data[i*stride+j]
+		}
+	}
+	return vecs
+}
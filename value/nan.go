@@ -0,0 +1,131 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// Special values. When the nan configuration is on, arithmetic with
+// no finite result yields an infinity or NaN rather than an error.
+// Infinities are ordinary infinite big.Floats, but a big.Float cannot
+// be NaN, so NaN is a BigFloat holding floatNaN, recognized by its
+// address. The operator dispatchers handle NaN operands, so floatNaN
+// is never used in arithmetic. Helpers such as floatLog and floatSqrt
+// may return floatNaN, though, so their callers must check for it
+// before using the result.
+
+var floatNaN = new(big.Float)
+
+var nan = BigFloat{floatNaN}
+
+// isNaN reports whether v is NaN.
+func isNaN(v Value) bool {
+	f, ok := v.(BigFloat)
+	return ok && f.Float == floatNaN
+}
+
+// isInf reports whether v is an infinity.
+func isInf(v Value) bool {
+	f, ok := v.(BigFloat)
+	return ok && f.Float != floatNaN && f.IsInf()
+}
+
+// divideByZero returns the result of dividing x by zero when the nan
+// configuration is on, an infinity with the sign of x or NaN if x is
+// zero. Otherwise it is an error.
+func divideByZero(c Context, x Value) Value {
+	if !c.Config().NaN() {
+		Errorf("division by zero")
+	}
+	if !toBool(x) {
+		return nan
+	}
+	return BigFloat{newFloat(c).SetInf(toBool(c.EvalBinary(x, "<", zero)))}
+}
+
+// zeroToNegativePower returns the result of raising zero to a negative
+// power when the nan configuration is on, which is an infinity.
+// Otherwise it is an error.
+func zeroToNegativePower(c Context) Value {
+	if !c.Config().NaN() {
+		Errorf("negative exponent of zero")
+	}
+	return BigFloat{newFloat(c).SetInf(false)}
+}
+
+// infOps holds the elementwise operators that are defined for
+// infinite operands. Others yield NaN.
+var infOps = map[string]bool{
+	"+":     true,
+	"-":     true,
+	"*":     true,
+	"/":     true,
+	"abs":   true,
+	"sgn":   true,
+	"float": true,
+	"floor": true,
+	"ceil":  true,
+	"round": true,
+	"log":   true,
+	"sqrt":  true,
+	"min":   true,
+	"max":   true,
+	"==":    true,
+	"!=":    true,
+	"<":     true,
+	"<=":    true,
+	">":     true,
+	">=":    true,
+}
+
+// nanCompare holds the results of comparisons with NaN, which is
+// unequal to everything.
+var nanCompare = map[string]Value{
+	"==": zero,
+	"!=": one,
+	"<":  zero,
+	"<=": zero,
+	">":  zero,
+	">=": zero,
+}
+
+// specialUnary returns the result of the elementwise unary operator
+// op applied to the scalar v, and true, if v is NaN or an infinity
+// that op does not handle.
+func specialUnary(op string, v Value) (Value, bool) {
+	if isNaN(v) || isInf(v) && !infOps[op] {
+		return nan, true
+	}
+	return nil, false
+}
+
+// specialBinary is like specialUnary for a binary operator.
+func specialBinary(op string, u, v Value) (Value, bool) {
+	if isNaN(u) || isNaN(v) {
+		if r, ok := nanCompare[op]; ok {
+			return r, true
+		}
+		return nan, true
+	}
+	if (isInf(u) || isInf(v)) && !infOps[op] {
+		return nan, true
+	}
+	return nil, false
+}
+
+// catchNaN is deferred by operations on floats to recover from the
+// panic big.Float raises for results such as 0/0 and Inf-Inf, and
+// to turn it into NaN or, if the nan configuration is off, an error.
+func catchNaN(c Context, result *Value) {
+	switch err := recover().(type) {
+	case nil:
+	case big.ErrNaN:
+		if !c.Config().NaN() {
+			Errorf("%s", err.Error())
+		}
+		*result = nan
+	default:
+		panic(err)
+	}
+}
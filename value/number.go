@@ -0,0 +1,164 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"math/big"
+	"sort"
+
+	"robpike.io/ivy/config"
+)
+
+// Number-theoretic functions on integers.
+
+// bigIntOf returns the integer value of v as a *big.Int.
+// The name is used in the error message if v is not an integer.
+func bigIntOf(name string, v Value) *big.Int {
+	switch x := v.(type) {
+	case Int:
+		return big.NewInt(int64(x))
+	case BigInt:
+		return x.Int
+	}
+	Errorf("%s: non-integer argument %s", name, v.Sprint(debugConf))
+	return nil
+}
+
+// powmod returns each element of v raised to the power exp modulo mod,
+// where u is the pair exp mod. The result is computed without forming
+// the full power. A negative exponent uses the modular inverse.
+func powmod(c Context, u, v Vector) Vector {
+	if len(u) != 2 {
+		Errorf("powmod: left operand must be exponent and modulus")
+	}
+	exp := bigIntOf("powmod", u[0])
+	mod := bigIntOf("powmod", u[1])
+	if mod.Sign() <= 0 {
+		Errorf("powmod: modulus must be positive")
+	}
+	elems := make([]Value, len(v))
+	for i, x := range v {
+		z := new(big.Int)
+		if z.Exp(bigIntOf("powmod", x), exp, mod) == nil {
+			Errorf("powmod: %s has no inverse modulo %s", x.Sprint(debugConf), mod)
+		}
+		elems[i] = BigInt{z}.shrink()
+	}
+	return NewVector(elems)
+}
+
+// primeWitnesses is the number of Miller-Rabin rounds used by the
+// primality tests. math/big also applies the Baillie-PSW test, so the
+// answer is exact for values less than 2⁶⁴ and, beyond that, wrong with
+// probability at most 4⁻²⁰.
+const primeWitnesses = 20
+
+func isprime(c Context, v Value) Value {
+	return toInt(bigIntOf("isprime", v).ProbablyPrime(primeWitnesses))
+}
+
+// nextprime returns the smallest prime strictly greater than v.
+func nextprime(c Context, v Value) Value {
+	n := new(big.Int).Set(bigIntOf("nextprime", v))
+	if n.Cmp(bigTwo.Int) < 0 {
+		return Int(2)
+	}
+	// Step to the next odd number, then through the odd numbers.
+	n.Add(n, bigOne.Int)
+	if n.Bit(0) == 0 {
+		n.Add(n, bigOne.Int)
+	}
+	for !n.ProbablyPrime(primeWitnesses) {
+		CheckInterrupt(c.Config())
+		n.Add(n, bigTwo.Int)
+	}
+	return BigInt{n}.shrink()
+}
+
+// trialDivisionBound is the limit of the small divisors tried by factor
+// before it resorts to Pollard's rho algorithm.
+const trialDivisionBound = 10000
+
+// factor returns the prime factors of v in increasing order, with
+// multiplicity. The factors of a negative number are those of its
+// absolute value preceded by -1, so the product of the factors is v.
+// The factors of 0 and 1 are the empty vector.
+func factor(c Context, v Value) Value {
+	n := new(big.Int).Set(bigIntOf("factor", v))
+	var factors []*big.Int
+	if n.Sign() < 0 {
+		factors = append(factors, big.NewInt(-1))
+		n.Neg(n)
+	}
+	if n.Sign() == 0 {
+		return NewVector(nil)
+	}
+	// Trial division by 2 and the odd numbers up to the bound.
+	d, q, r := big.NewInt(2), new(big.Int), new(big.Int)
+	for d.Int64() <= trialDivisionBound && n.Cmp(bigOne.Int) > 0 {
+		if q.QuoRem(n, d, r); r.Sign() == 0 {
+			factors = append(factors, new(big.Int).Set(d))
+			n.Set(q)
+			continue
+		}
+		if d.Int64() == 2 {
+			d.SetInt64(3)
+		} else {
+			d.Add(d, bigTwo.Int)
+		}
+	}
+	large := pollardFactors(c.Config(), n, nil)
+	sort.Slice(large, func(i, j int) bool { return large[i].Cmp(large[j]) < 0 })
+	factors = append(factors, large...)
+	elems := make([]Value, len(factors))
+	for i, f := range factors {
+		elems[i] = BigInt{f}.shrink()
+	}
+	return NewVector(elems)
+}
+
+// pollardFactors appends the prime factors of n, which has no small
+// factors, to factors, splitting composites with Pollard's rho algorithm.
+func pollardFactors(conf *config.Config, n *big.Int, factors []*big.Int) []*big.Int {
+	if n.Cmp(bigOne.Int) <= 0 {
+		return factors
+	}
+	if n.ProbablyPrime(primeWitnesses) {
+		return append(factors, n)
+	}
+	d := pollardRho(conf, n)
+	factors = pollardFactors(conf, d, factors)
+	return pollardFactors(conf, new(big.Int).Quo(n, d), factors)
+}
+
+// pollardRho returns a non-trivial factor of the composite n. It uses
+// the iteration x ← x²+k mod n with Floyd's cycle detection, trying
+// successive values of k until a factor appears.
+func pollardRho(conf *config.Config, n *big.Int) *big.Int {
+	step := func(x, k *big.Int) {
+		x.Mul(x, x)
+		x.Add(x, k)
+		x.Mod(x, n)
+	}
+	diff, d := new(big.Int), new(big.Int)
+	for k := big.NewInt(1); ; k.Add(k, bigOne.Int) {
+		x, y := big.NewInt(2), big.NewInt(2)
+		for {
+			// This can take a very long time, so allow it to be stopped.
+			CheckInterrupt(conf)
+			step(x, k)
+			step(y, k)
+			step(y, k)
+			diff.Sub(x, y)
+			d.GCD(nil, nil, diff.Abs(diff), n)
+			if d.Cmp(bigOne.Int) != 0 {
+				break
+			}
+		}
+		if d.Cmp(n) != 0 {
+			return d
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Parallel reduction. A reduction by an associative operator may be
+// computed by reducing pieces of the vector independently and then
+// reducing the partial results. It is worth doing only for large
+// vectors, and only for exact values, for which the grouping cannot
+// change the result.
+
+// parallelThreshold is the smallest vector reduced in parallel.
+const parallelThreshold = 100000
+
+// associativeOps holds the builtin operators for which grouping does
+// not affect the result of a reduction of exact values.
+var associativeOps = map[string]bool{
+	"+":   true,
+	"*":   true,
+	"max": true,
+	"min": true,
+	"and": true,
+	"or":  true,
+	"&":   true,
+	"|":   true,
+	"^":   true,
+	"gcd": true,
+	"lcm": true,
+}
+
+// canReduceInParallel reports whether v may be reduced by op in parallel.
+func canReduceInParallel(c Context, op string, v Vector) bool {
+	if len(v) < parallelThreshold || !associativeOps[op] || c.UserDefined(op, true) {
+		return false
+	}
+	// Overflow warnings must be printed in order.
+	if !c.Config().Parallel() || c.Config().WarnOverflow() || runtime.NumCPU() < 2 {
+		return false
+	}
+	for _, x := range v {
+		switch x.(type) {
+		case Int, BigInt, BigRat:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parallelReduce reduces v by op, dividing the work among the CPUs.
+// An error in any piece is reported as if the reduction were serial.
+func parallelReduce(c Context, op string, v Vector) Value {
+	n := runtime.NumCPU()
+	chunk := (len(v) + n - 1) / n
+	partial := make([]Value, 0, n)
+	for start := 0; start < len(v); start += chunk {
+		partial = append(partial, nil)
+	}
+	panics := make([]interface{}, len(partial))
+	var wg sync.WaitGroup
+	for i := range partial {
+		start := i * chunk
+		end := start + chunk
+		if end > len(v) {
+			end = len(v)
+		}
+		wg.Add(1)
+		go func(i int, v Vector) {
+			defer wg.Done()
+			defer func() {
+				panics[i] = recover()
+			}()
+			partial[i] = reduceVector(c, op, v)
+		}(i, v[start:end])
+	}
+	wg.Wait()
+	for _, p := range panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+	return reduceVector(c, op, partial)
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "sort"
+
+// Polynomials. A polynomial is a vector of coefficients, highest
+// power first, so 1 -3 2 is x²-3x+2.
+
+// polyval returns the value of the polynomial with coefficients u at
+// each element of v, computed by Horner's rule.
+func polyval(c Context, u, v Value) Value {
+	var coeffs Vector
+	switch u := u.(type) {
+	case Vector:
+		coeffs = u
+	case *Matrix:
+		Errorf("polyval: coefficients must be a vector")
+	default:
+		coeffs = Vector{u}
+	}
+	if len(coeffs) == 0 {
+		Errorf("polyval: no coefficients")
+	}
+	eval := func(x Value) Value {
+		acc := coeffs[0]
+		for _, a := range coeffs[1:] {
+			acc = c.EvalBinary(c.EvalBinary(acc, "*", x), "+", a)
+		}
+		return acc
+	}
+	switch v := v.(type) {
+	case Vector:
+		w := make(Vector, len(v))
+		for i, x := range v {
+			w[i] = eval(x)
+		}
+		return w
+	case *Matrix:
+		w := make(Vector, len(v.data))
+		for i, x := range v.data {
+			w[i] = eval(x)
+		}
+		return NewMatrix(v.shape, w)
+	}
+	return eval(v)
+}
+
+// roots returns the roots of the polynomial with coefficients v,
+// found by the Durand-Kerner method at the float precision. Roots
+// whose imaginary part is negligible are returned as reals. The
+// roots are sorted by real part, then imaginary part, treating real
+// parts that differ only by rounding error as equal.
+func roots(c Context, v Vector) Value {
+	for len(v) > 0 && !toBool(v[0]) {
+		v = v[1:]
+	}
+	if len(v) == 0 {
+		Errorf("roots: zero polynomial")
+	}
+	n := len(v) - 1
+	if n == 0 {
+		return Vector{}
+	}
+	// Make the polynomial monic.
+	monic := make(Vector, len(v))
+	for i, a := range v {
+		monic[i] = c.EvalBinary(a, "/", v[0])
+	}
+	// Start from powers of a number that is neither real
+	// nor a root of unity.
+	prec := c.Config().FloatPrec()
+	z := make([]Value, n)
+	seed := Complex{BigFloat{newFloat(c).SetFloat64(0.4)}, BigFloat{newFloat(c).SetFloat64(0.9)}}
+	var w Value = one
+	for i := range z {
+		w = c.EvalBinary(w, "*", seed)
+		z[i] = w
+	}
+	eps := BigFloat{newFloat(c).SetMantExp(newFloat(c).SetInt64(1), 8-int(prec))}
+	for iter := 0; iter < 100+int(prec); iter++ {
+		done := true
+		for i, zi := range z {
+			den := Value(one)
+			for j, zj := range z {
+				if j != i {
+					den = c.EvalBinary(den, "*", c.EvalBinary(zi, "-", zj))
+				}
+			}
+			delta := c.EvalBinary(polyval(c, monic, zi), "/", den)
+			z[i] = c.EvalBinary(zi, "-", delta)
+			scale := c.EvalBinary(one, "+", c.EvalUnary("abs", z[i]))
+			if toBool(c.EvalBinary(c.EvalUnary("abs", delta), ">", c.EvalBinary(eps, "*", scale))) {
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+	}
+	// Repeated roots converge more slowly, so be generous
+	// when deciding that a part of a root is zero.
+	tol := BigFloat{newFloat(c).SetMantExp(newFloat(c).SetInt64(1), -int(prec)/2)}
+	for i, zi := range z {
+		x, ok := zi.(Complex)
+		if !ok {
+			continue
+		}
+		small := c.EvalBinary(tol, "*", c.EvalBinary(one, "+", c.EvalUnary("abs", x)))
+		negligible := func(x Value) bool {
+			return toBool(c.EvalBinary(c.EvalUnary("abs", x), "<=", small))
+		}
+		switch {
+		case negligible(x.imag):
+			z[i] = x.real
+		case negligible(x.real):
+			z[i] = newComplex(zero, x.imag)
+		}
+	}
+	parts := func(x Value) (re, im Value) {
+		if x, ok := x.(Complex); ok {
+			return x.real, x.imag
+		}
+		return x, zero
+	}
+	sort.SliceStable(z, func(i, j int) bool {
+		ri, ii := parts(z[i])
+		rj, ij := parts(z[j])
+		diff := c.EvalUnary("abs", c.EvalBinary(ri, "-", rj))
+		scale := c.EvalBinary(one, "+", c.EvalUnary("abs", ri))
+		if toBool(c.EvalBinary(diff, ">", c.EvalBinary(tol, "*", scale))) {
+			return toBool(c.EvalBinary(ri, "<", rj))
+		}
+		return toBool(c.EvalBinary(ii, "<", ij))
+	})
+	return NewVector(z)
+}
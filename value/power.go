@@ -5,6 +5,7 @@
 package value
 
 import (
+	"math"
 	"math/big"
 
 	"robpike.io/ivy/config"
@@ -15,6 +16,109 @@ func power(c Context, u, v Value) Value {
 	return BigFloat{z}.shrink()
 }
 
+// root returns the u'th root of v. The degree u must be a positive
+// integer. If v is exact and a perfect power, so is the result.
+func root(c Context, u, v Value) Value {
+	var n *big.Rat
+	if f, ok := u.(BigFloat); ok {
+		n, _ = f.Rat(nil)
+	} else {
+		n = exactRat(u)
+	}
+	if !n.IsInt() || n.Sign() <= 0 || !n.Num().IsInt64() {
+		Errorf("root: degree must be a positive integer")
+	}
+	deg := n.Num().Int64()
+	switch x := v.(type) {
+	case BigFloat:
+		if x.Sign() < 0 {
+			if deg%2 == 0 {
+				if c.Config().NaN() {
+					return nan
+				}
+				Errorf("root: even root of negative number")
+			}
+			return c.EvalUnary("-", root(c, u, c.EvalUnary("-", v)))
+		}
+	default:
+		r := exactRat(v)
+		if r.Sign() < 0 {
+			if deg%2 == 0 {
+				if c.Config().NaN() {
+					return nan
+				}
+				Errorf("root: even root of negative number")
+			}
+			return c.EvalUnary("-", root(c, u, c.EvalUnary("-", v)))
+		}
+		num, numOK := bigIntRoot(r.Num(), deg)
+		den, denOK := bigIntRoot(r.Denom(), deg)
+		if numOK && denOK {
+			return BigRat{new(big.Rat).SetFrac(num, den)}.shrink()
+		}
+	}
+	return power(c, v, BigRat{new(big.Rat).Inv(n)})
+}
+
+// bigIntRoot returns the integer part of the n'th root of the
+// non-negative a, and whether the root is exact. It uses Newton's
+// method, which decreases monotonically to the root from above.
+func bigIntRoot(a *big.Int, n int64) (*big.Int, bool) {
+	if a.Sign() == 0 {
+		return new(big.Int), true
+	}
+	// If a < 2**n, the root is less than 2. Catching this here also
+	// keeps a huge n from being used as an exponent below.
+	if n >= int64(a.BitLen()) {
+		return big.NewInt(1), a.Cmp(bigOne.Int) == 0
+	}
+	bn := big.NewInt(n)
+	bn1 := big.NewInt(n - 1)
+	x := rootGuess(a, n)
+	y := new(big.Int)
+	t := new(big.Int)
+	for {
+		// y = ((n-1)x + a/x**(n-1)) / n
+		t.Exp(x, bn1, nil)
+		t.Quo(a, t)
+		y.Mul(bn1, x)
+		y.Add(y, t)
+		y.Quo(y, bn)
+		if y.Cmp(x) >= 0 {
+			break
+		}
+		x, y = y, x
+	}
+	return x, t.Exp(x, bn, nil).Cmp(a) == 0
+}
+
+// rootGuess returns a number a little above the n'th root of a, which
+// is at least 2, computed in floating point. Starting close to the root
+// matters: from far above it, Newton's method approaches the root by
+// only about a factor of (n-1)/n each step.
+func rootGuess(a *big.Int, n int64) *big.Int {
+	mant := new(big.Float)
+	e := new(big.Float).SetInt(a).MantExp(mant)
+	f, _ := mant.Float64()
+	lg := (float64(e) + math.Log2(f)) / float64(n)
+	ie := math.Floor(lg)
+	// The root is 2**(lg-ie) * 2**ie, with the first factor in [1, 2).
+	// Keep 52 bits of it and round up by about one part in a million.
+	x := new(big.Int).SetUint64(uint64(math.Exp2(lg-ie)*(1+1.0/(1<<20))*(1<<52)) + 1)
+	if shift := int(ie) - 52; shift >= 0 {
+		x.Lsh(x, uint(shift))
+	} else {
+		x.Rsh(x, uint(-shift))
+		x.Add(x, bigOne.Int)
+	}
+	// Rounding error can leave the guess below the root; nudge it up.
+	bn := big.NewInt(n)
+	for t := new(big.Int); t.Exp(x, bn, nil).Cmp(a) < 0; {
+		x.Add(x, new(big.Int).Add(new(big.Int).Rsh(x, 20), bigOne.Int))
+	}
+	return x
+}
+
 func exp(c Context, u Value) Value {
 	z := exponential(c.Config(), floatSelf(c, u).(BigFloat).Float)
 	return BigFloat{z}.shrink()
@@ -31,7 +135,7 @@ func floatPower(c Context, bx, bexp BigFloat) *big.Float {
 		return newFloat(c).SetInt64(1)
 	case -1:
 		if x.Sign() == 0 {
-			Errorf("negative exponent of zero")
+			return zeroToNegativePower(c).(BigFloat).Float
 		}
 		positive = false
 		fexp = c.EvalUnary("-", bexp).toType(conf, bigFloatType).(BigFloat).Float
@@ -42,6 +146,9 @@ func floatPower(c Context, bx, bexp BigFloat) *big.Float {
 		return x
 	case fexp.Cmp(floatHalf) == 0:
 		z := floatSqrt(c, x)
+		if z == floatNaN {
+			return z
+		}
 		if !positive {
 			z = z.Quo(floatOne, z)
 		}
@@ -59,6 +166,9 @@ func floatPower(c Context, bx, bexp BigFloat) *big.Float {
 		frac := fexp.Sub(fexp, newFloat(c).SetInt64(exp))
 		// x**frac is e**(frac*log x)
 		logx := floatLog(c, x)
+		if logx == floatNaN {
+			return logx
+		}
 		frac.Mul(frac, logx)
 		z.Mul(z, exponential(c.Config(), frac))
 	}
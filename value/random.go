@@ -0,0 +1,70 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// randomArray returns an array of random floats, each made by gen,
+// whose shape is given by v: a count for a vector, or a vector of
+// dimensions.
+func randomArray(c Context, op string, v Value, gen func(Context) *big.Float) Value {
+	var dims []Value
+	switch v := v.(type) {
+	case Int:
+		dims = []Value{v}
+	case Vector:
+		dims = v
+	default:
+		Errorf("%s: shape must be small non-negative integers", op)
+	}
+	shape := make([]int, len(dims))
+	n := 1
+	for i, d := range dims {
+		k, ok := d.(Int)
+		if !ok || k < 0 {
+			Errorf("%s: shape must be small non-negative integers", op)
+		}
+		shape[i] = int(k)
+		if n *= int(k); n > 1e8 {
+			Errorf("%s: result too large", op)
+		}
+	}
+	data := make(Vector, n)
+	for i := range data {
+		data[i] = BigFloat{gen(c)}
+	}
+	if len(shape) == 1 {
+		return data
+	}
+	return NewMatrix(shape, data)
+}
+
+// randomFloat returns a uniformly distributed random float in [0, 1)
+// with all the bits of the configured precision random.
+func randomFloat(c Context) *big.Float {
+	conf := c.Config()
+	prec := conf.FloatPrec()
+	limit := new(big.Int).Lsh(bigOne.Int, prec)
+	n := new(big.Int).Rand(conf.Random(), limit)
+	z := newFloat(c).SetInt(n)
+	return z.SetMantExp(z, -int(prec))
+}
+
+// randomNormal returns a random float from the standard normal
+// distribution, using the Box-Muller transform
+//
+//	z = sqrt(-2 log u) cos(2πv)
+//
+// for u and v uniform, with u in (0, 1] so the logarithm is defined.
+func randomNormal(c Context) *big.Float {
+	u := newFloat(c).Sub(floatOne, randomFloat(c))
+	r := floatLog(c, u)
+	r.Mul(r, floatMinusOne)
+	r.Mul(r, floatTwo)
+	r = floatSqrt(c, r)
+	theta := newFloat(c).Mul(floatPi, floatTwo)
+	theta.Mul(theta, randomFloat(c))
+	return r.Mul(r, floatCos(c, theta))
+}
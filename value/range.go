@@ -0,0 +1,222 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"math/big"
+
+	"robpike.io/ivy/config"
+)
+
+// Range is a vector of integers in arithmetic progression, such as
+// iota n, that is not materialized: it holds only the first element,
+// the difference between successive elements, and the number of
+// elements. Reductions, indexing by a scalar and simple arithmetic
+// work on that description directly. Anything else sees the equivalent
+// Vector, which is built afresh each time it is needed.
+//
+// A Range is never empty, and all its elements fit in an Int.
+type Range struct {
+	start Int
+	step  Int
+	count int
+}
+
+// newRange returns the Range of count elements running from first to
+// last, if those are small integers.
+func newRange(first, last int64, count int) (Value, bool) {
+	if first < minInt || maxInt < first || last < minInt || maxInt < last {
+		return nil, false
+	}
+	r := Range{start: Int(first), count: count}
+	if count > 1 {
+		r.step = Int((last - first) / int64(count-1))
+	}
+	return r, true
+}
+
+func (r Range) String() string {
+	return "(" + r.Sprint(debugConf) + ")"
+}
+
+func (r Range) Sprint(conf *config.Config) string {
+	return r.materialize(conf).Sprint(conf)
+}
+
+func (r Range) Rank() int {
+	return 1
+}
+
+func (r Range) ProgString() string {
+	// Like a vector, a range never appears in a program listing.
+	panic("range.ProgString - cannot happen")
+}
+
+func (r Range) Eval(Context) Value {
+	return r
+}
+
+func (r Range) Inner() Value {
+	return r
+}
+
+// Len returns the number of elements in the range.
+func (r Range) Len() int {
+	return r.count
+}
+
+// At returns the element of the range at index i, counting from 0.
+func (r Range) At(i int) Int {
+	return r.start + r.step*Int(i)
+}
+
+func (r Range) last() Int {
+	return r.At(r.count - 1)
+}
+
+func (r Range) toType(conf *config.Config, which valueType) Value {
+	switch which {
+	case vectorType:
+		return r.materialize(conf)
+	case matrixType:
+		return NewMatrix([]int{r.count}, r.materialize(conf))
+	}
+	Errorf("cannot convert vector to %s", which)
+	return nil
+}
+
+// materialize returns the elements of r as a new Vector.
+func (r Range) materialize(conf *config.Config) Vector {
+	v := make([]Value, r.count)
+	for i := range v {
+		if i%(1<<16) == 0 {
+			CheckInterrupt(conf)
+		}
+		v[i] = r.At(i)
+	}
+	return NewVector(v)
+}
+
+// Materialize returns v, or the Vector of its elements if v is a Range.
+// Code that looks inside vectors must call it before doing so.
+func Materialize(conf *config.Config, v Value) Value {
+	if r, ok := v.(Range); ok {
+		return r.materialize(conf)
+	}
+	return v
+}
+
+// unary applies the builtin unary operator op to r, if it can
+// be done without materializing r.
+func (r Range) unary(c Context, op string) (Value, bool) {
+	switch op {
+	case "rho":
+		return Int(r.count), true
+	case "-":
+		if c.Config().IntBits() == 0 {
+			return newRange(-int64(r.start), -int64(r.last()), r.count)
+		}
+	}
+	return nil, false
+}
+
+// rangeBinary applies the builtin binary operator op to u and v, at
+// least one of which is a Range, if it can be done without materializing
+// the range. Arithmetic is done only if its result is a range of small
+// integers, so no element can overflow.
+func rangeBinary(c Context, u Value, op string, v Value) (Value, bool) {
+	ur, uok := u.(Range)
+	vr, vok := v.(Range)
+	if !uok && !vok {
+		return nil, false
+	}
+	if op == "[]" {
+		if i, ok := v.(Int); ok && uok {
+			i -= Int(c.Config().Origin())
+			if 0 <= i && int(i) < ur.count {
+				return ur.At(int(i)), true
+			}
+		}
+		return nil, false
+	}
+	if c.Config().IntBits() != 0 {
+		return nil, false
+	}
+	switch {
+	case uok && vok:
+		if ur.count != vr.count {
+			return nil, false
+		}
+		first, last := int64(vr.start), int64(vr.last())
+		switch op {
+		case "+":
+			return newRange(int64(ur.start)+first, int64(ur.last())+last, ur.count)
+		case "-":
+			return newRange(int64(ur.start)-first, int64(ur.last())-last, ur.count)
+		}
+	case uok:
+		if k, ok := v.(Int); ok {
+			return ur.scalar(k, op, false)
+		}
+	case vok:
+		if k, ok := u.(Int); ok {
+			return vr.scalar(k, op, true)
+		}
+	}
+	return nil, false
+}
+
+// scalar applies op to r and k, as r op k or, if reversed, k op r.
+func (r Range) scalar(k Int, op string, reversed bool) (Value, bool) {
+	first, last, n := int64(r.start), int64(r.last()), int64(k)
+	switch op {
+	case "+":
+		return newRange(first+n, last+n, r.count)
+	case "-":
+		if reversed {
+			return newRange(n-first, n-last, r.count)
+		}
+		return newRange(first-n, last-n, r.count)
+	case "*":
+		return newRange(first*n, last*n, r.count)
+	}
+	return nil, false
+}
+
+// reduceRange reduces r by op. Sums, maxima and minima by builtin
+// operators have closed forms; other operators are applied to each
+// element in turn.
+func reduceRange(c Context, op string, r Range) Value {
+	last := r.last()
+	if !c.UserDefined(op, true) {
+		switch op {
+		case "+":
+			// n*start + step*n(n-1)/2, computed without overflow
+			// and wrapped once, if )intbits is set, as the sum of
+			// the elements would be.
+			n := big.NewInt(int64(r.count))
+			sum := new(big.Int).Mul(n, big.NewInt(int64(r.count-1)))
+			sum.Rsh(sum, 1)
+			sum.Mul(sum, big.NewInt(int64(r.step)))
+			sum.Add(sum, n.Mul(n, big.NewInt(int64(r.start))))
+			return wrap(c, BigInt{sum}.shrink())
+		case "max":
+			if r.step < 0 {
+				return r.start
+			}
+			return last
+		case "min":
+			if r.step < 0 {
+				return last
+			}
+			return r.start
+		}
+	}
+	var acc Value = last
+	for i := r.count - 2; i >= 0; i-- {
+		acc = c.EvalBinary(r.At(i), op, acc)
+	}
+	return acc
+}
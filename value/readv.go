@@ -0,0 +1,63 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"bufio"
+	"strings"
+	"unicode/utf8"
+)
+
+// NiladicOps holds the operators that take no operands. An identifier
+// that names one calls it when there is no variable by that name.
+var NiladicOps = map[string]func(Context) Value{
+	"now":   now,
+	"readv": readVector,
+}
+
+// readVector reads the rest of the configured input, normally the
+// standard input, and returns the whitespace-separated words in it. If
+// they are all numbers, the result is a numeric vector; otherwise it is
+// a char matrix with one word per row.
+func readVector(c Context) Value {
+	conf := c.Config()
+	scanner := bufio.NewScanner(conf.Input())
+	scanner.Buffer(nil, 1<<20)
+	scanner.Split(bufio.ScanWords)
+	var nums []Value
+	var words []string
+	numeric := true
+	for scanner.Scan() {
+		word := scanner.Text()
+		words = append(words, word)
+		if numeric {
+			x, ok := parseNumber(conf, word)
+			if ok && x.Rank() == 0 {
+				nums = append(nums, x)
+			} else {
+				numeric = false
+				nums = nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		Errorf("readv: %s", err)
+	}
+	if numeric {
+		return NewVector(nums)
+	}
+	width := 0
+	for _, word := range words {
+		if n := utf8.RuneCountInString(word); width < n {
+			width = n
+		}
+	}
+	data := make([]Value, 0, len(words)*width)
+	for _, word := range words {
+		word += strings.Repeat(" ", width-utf8.RuneCountInString(word))
+		data = append(data, newString(word)...)
+	}
+	return NewMatrix([]int{len(words), width}, data)
+}
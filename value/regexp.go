@@ -0,0 +1,104 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Regular expressions. The binary match reports whether the pattern A
+// matches the text B, and find returns the substrings of B that A
+// matches. Patterns use Go's regexp syntax.
+
+// regexpCache holds compiled patterns, as an expression applied to many
+// texts would otherwise compile its pattern each time.
+var regexpCache struct {
+	sync.Mutex
+	re map[string]*regexp.Regexp
+}
+
+// maxRegexpCache bounds the size of regexpCache.
+const maxRegexpCache = 100
+
+// compileRegexp returns the compiled pattern u.
+func compileRegexp(name string, u Value) *regexp.Regexp {
+	pattern := goString(name, u)
+	regexpCache.Lock()
+	defer regexpCache.Unlock()
+	if re, ok := regexpCache.re[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		Errorf("%s: %s", name, err)
+	}
+	if regexpCache.re == nil || len(regexpCache.re) >= maxRegexpCache {
+		regexpCache.re = make(map[string]*regexp.Regexp)
+	}
+	regexpCache.re[pattern] = re
+	return re
+}
+
+// match returns 1 if the pattern u matches the string v and 0
+// otherwise. If v is a char matrix, or a vector of enclosed strings,
+// the result is a vector with an element for each row or string.
+// Trailing blanks that pad the rows of a matrix are ignored.
+func match(c Context, u, v Value) Value {
+	re := compileRegexp("match", u)
+	switch v := v.(type) {
+	case *Matrix:
+		if v.Rank() != 2 {
+			Errorf("match: text must be a string, char matrix, or vector of strings")
+		}
+		ncols := v.shape[1]
+		res := make([]Value, v.shape[0])
+		for i := range res {
+			row := goString("match", NewVector(v.data[i*ncols:(i+1)*ncols]))
+			res[i] = toInt(re.MatchString(strings.TrimRight(row, " ")))
+		}
+		return NewVector(res)
+	case Vector:
+		if v.hasBox() {
+			res := make([]Value, len(v))
+			for i, x := range v {
+				if b, ok := x.(Box); ok {
+					x = b.value
+				}
+				res[i] = toInt(re.MatchString(goString("match", x)))
+			}
+			return NewVector(res)
+		}
+	}
+	return toInt(re.MatchString(goString("match", v)))
+}
+
+// find returns the substrings of the string v matched by the pattern u,
+// as a vector of enclosed strings. If the pattern has capture groups,
+// the result is instead a matrix with a row for each match holding the
+// text of each group.
+func find(c Context, u, v Value) Value {
+	re := compileRegexp("find", u)
+	matches := re.FindAllStringSubmatch(goString("find", v), -1)
+	groups := re.NumSubexp()
+	if groups == 0 {
+		res := make([]Value, len(matches))
+		for i, m := range matches {
+			res[i] = Box{newString(m[0])}
+		}
+		return NewVector(res)
+	}
+	if len(matches) == 0 {
+		return NewVector([]Value{})
+	}
+	data := make([]Value, 0, len(matches)*groups)
+	for _, m := range matches {
+		for _, s := range m[1:] {
+			data = append(data, Box{newString(s)})
+		}
+	}
+	return NewMatrix([]int{len(matches), groups}, data)
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// sh runs the shell command v, which is permitted only when )shell is
+// on, and returns its standard output without the final newline, so
+// a one-line result prints as a single line. If the command fails, the
+// error holds what it printed to standard error.
+func sh(c Context, v Value) Value {
+	if !c.Config().Shell() {
+		Errorf("sh: shell commands are disabled; use )shell on")
+	}
+	cmd := exec.Command("sh", "-c", goString("sh", v))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		Errorf("sh: %s", msg)
+	}
+	return newString(strings.TrimSuffix(stdout.String(), "\n"))
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+func sinh(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatSinh)
+}
+
+func cosh(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatCosh)
+}
+
+func tanh(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatTanh)
+}
+
+func asinh(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatAsinh)
+}
+
+func acosh(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatAcosh)
+}
+
+func atanh(c Context, v Value) Value {
+	return evalFloatFunc(c, v, floatAtanh)
+}
+
+// expPair returns e**x and e**-x.
+func expPair(c Context, x *big.Float) (*big.Float, *big.Float) {
+	ex := exponential(c.Config(), x)
+	return ex, newFloat(c).Quo(floatOne, ex)
+}
+
+// floatSinh computes sinh(x) = (e**x - e**-x)/2.
+func floatSinh(c Context, x *big.Float) *big.Float {
+	// The formula loses precision near zero, where the terms cancel.
+	// Use the odd symmetry and the Taylor series x + x³/3! + x⁵/5! ...
+	// for small x.
+	if x.MantExp(nil) <= 0 { // |x| < 1
+		x2 := newFloat(c).Mul(x, x)
+		term := newFloat(c).Set(x)
+		z := newFloat(c).Set(x)
+		n := newFloat(c)
+		for loop := newLoop(c.Config(), "sinh", x, 1); ; {
+			k := 2 * (loop.i + 1)
+			term.Mul(term, x2)
+			term.Quo(term, n.SetUint64(k*(k+1)))
+			z.Add(z, term)
+			if loop.done(z) {
+				break
+			}
+		}
+		return z
+	}
+	ex, enx := expPair(c, x)
+	ex.Sub(ex, enx)
+	return ex.Quo(ex, floatTwo)
+}
+
+// floatCosh computes cosh(x) = (e**x + e**-x)/2.
+func floatCosh(c Context, x *big.Float) *big.Float {
+	ex, enx := expPair(c, x)
+	ex.Add(ex, enx)
+	return ex.Quo(ex, floatTwo)
+}
+
+// floatTanh computes tanh(x) = sinh(x)/cosh(x).
+func floatTanh(c Context, x *big.Float) *big.Float {
+	z := floatSinh(c, x)
+	return z.Quo(z, floatCosh(c, x))
+}
+
+// floatAsinh computes asinh(x) = log(x + sqrt(x²+1)).
+func floatAsinh(c Context, x *big.Float) *big.Float {
+	// asinh(-x) == -asinh(x), and the formula is accurate for positive x.
+	if x.Sign() < 0 {
+		z := newFloat(c).Neg(x)
+		z = floatAsinh(c, z)
+		return z.Neg(z)
+	}
+	z := newFloat(c).Mul(x, x)
+	z.Add(z, floatOne)
+	z = floatSqrt(c, z)
+	z.Add(z, x)
+	return floatLog(c, z)
+}
+
+// floatAcosh computes acosh(x) = log(x + sqrt(x²-1)).
+func floatAcosh(c Context, x *big.Float) *big.Float {
+	if x.Cmp(floatOne) < 0 {
+		Errorf("acosh of value less than 1")
+	}
+	z := newFloat(c).Mul(x, x)
+	z.Sub(z, floatOne)
+	z = floatSqrt(c, z)
+	z.Add(z, x)
+	return floatLog(c, z)
+}
+
+// floatAtanh computes atanh(x) = log((1+x)/(1-x))/2.
+func floatAtanh(c Context, x *big.Float) *big.Float {
+	if x.Cmp(floatMinusOne) <= 0 || x.Cmp(floatOne) >= 0 {
+		Errorf("atanh of value with magnitude not less than 1")
+	}
+	num := newFloat(c).Add(floatOne, x)
+	den := newFloat(c).Sub(floatOne, x)
+	z := floatLog(c, num.Quo(num, den))
+	return z.Quo(z, floatTwo)
+}
@@ -19,10 +19,16 @@ func evalFloatFunc(c Context, v Value, fn func(Context, *big.Float) *big.Float)
 func floatSqrt(c Context, x *big.Float) *big.Float {
 	switch x.Sign() {
 	case -1:
+		if c.Config().NaN() {
+			return floatNaN
+		}
 		Errorf("square root of negative number")
 	case 0:
 		return newFloat(c)
 	}
+	if x.IsInf() {
+		return x
+	}
 
 	// Each iteration computes
 	// 	z = z - (z²-x)/2z
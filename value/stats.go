@@ -0,0 +1,81 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+// Statistics of numeric vectors. They use the generic arithmetic
+// operators, so the mean and variance of integers and rationals are
+// exact.
+
+// statistic applies fn to v, or to each vector along the last axis
+// of v if it is a matrix. A scalar is treated as a one-element vector.
+func statistic(c Context, op string, v Value, fn func(Context, string, Vector) Value) Value {
+	switch v := v.(type) {
+	case Vector:
+		return fn(c, op, v)
+	case *Matrix:
+		n := v.shape[len(v.shape)-1]
+		shape := v.shape[:len(v.shape)-1]
+		data := make(Vector, size(shape))
+		for i := range data {
+			data[i] = fn(c, op, v.data[i*n:(i+1)*n])
+		}
+		if len(shape) == 1 {
+			return data
+		}
+		return NewMatrix(shape, data)
+	}
+	return fn(c, op, NewVector([]Value{v}))
+}
+
+// sum returns the sum of the elements of v, which must not be empty.
+func sum(c Context, op string, v Vector) Value {
+	if len(v) == 0 {
+		Errorf("%s: empty vector", op)
+	}
+	acc := v[0]
+	for _, x := range v[1:] {
+		acc = c.EvalBinary(acc, "+", x)
+	}
+	return acc
+}
+
+// mean returns the arithmetic mean of v.
+func mean(c Context, op string, v Vector) Value {
+	return c.EvalBinary(sum(c, op, v), "/", Int(len(v)))
+}
+
+// variance returns the sample variance of v, the sum of the squared
+// deviations from the mean divided by one less than the length.
+func variance(c Context, op string, v Vector) Value {
+	if len(v) < 2 {
+		Errorf("%s: need at least two values", op)
+	}
+	m := mean(c, op, v)
+	dev := make(Vector, len(v))
+	for i, x := range v {
+		d := c.EvalBinary(x, "-", m)
+		dev[i] = c.EvalBinary(d, "*", d)
+	}
+	return c.EvalBinary(sum(c, op, dev), "/", Int(len(v)-1))
+}
+
+// stddev returns the sample standard deviation of v.
+func stddev(c Context, op string, v Vector) Value {
+	return c.EvalUnary("sqrt", variance(c, op, v))
+}
+
+// median returns the middle value of v once sorted, or the mean of
+// the two middle values if v has even length.
+func median(c Context, op string, v Vector) Value {
+	if len(v) == 0 {
+		Errorf("%s: empty vector", op)
+	}
+	s := v.sorted(c, false)
+	mid := len(s) / 2
+	if len(s)%2 == 1 {
+		return s[mid]
+	}
+	return c.EvalBinary(c.EvalBinary(s[mid-1], "+", s[mid]), "/", Int(2))
+}
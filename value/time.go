@@ -0,0 +1,245 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"robpike.io/ivy/config"
+)
+
+// Time is an instant in time. Times are made by date and now, and
+// differ from other scalars in the arithmetic they allow: adding or
+// subtracting a number of seconds yields a Time, and subtracting two
+// Times yields the number of seconds between them. Times compare by
+// the order of the instants.
+type Time struct {
+	t time.Time
+}
+
+// dateLayouts holds the layouts date accepts for times written as
+// strings. Times without a zone are in UTC.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Time returns the time held by t.
+func (t Time) Time() time.Time {
+	return t.t
+}
+
+func (t Time) String() string {
+	return "(" + t.Sprint(debugConf) + ")"
+}
+
+func (t Time) Rank() int {
+	return 0
+}
+
+// Sprint prints the time using the configured date format, or in
+// RFC 3339 format if there is none.
+func (t Time) Sprint(conf *config.Config) string {
+	layout := conf.DateFormat()
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.t.Format(layout)
+}
+
+func (t Time) ProgString() string {
+	return fmt.Sprintf("(date %q)", t.t.Format(time.RFC3339Nano))
+}
+
+func (t Time) Eval(Context) Value {
+	return t
+}
+
+func (t Time) Inner() Value {
+	return t
+}
+
+func (t Time) toType(conf *config.Config, which valueType) Value {
+	switch which {
+	case timeType:
+		return t
+	case vectorType:
+		return NewVector([]Value{t})
+	case matrixType:
+		return NewMatrix([]int{1}, []Value{t})
+	}
+	Errorf("cannot convert time to %s", which)
+	return nil
+}
+
+// now returns the current time.
+func now(c Context) Value {
+	return Time{time.Now()}
+}
+
+// date returns the time written as the string v, or, if v is a
+// number, that many seconds after the Unix epoch, 1970-01-01 UTC.
+func date(c Context, v Value) Value {
+	switch v := v.(type) {
+	case Time:
+		return v
+	case Char, Vector:
+		s := goString("date", v)
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return Time{t}
+			}
+		}
+		Errorf("date: cannot parse %q", s)
+	}
+	return Time{addNanoseconds(time.Unix(0, 0).UTC(), nanoseconds(c, v))}
+}
+
+// formatTime returns the time v formatted according to the Go time
+// layout u, as in "2006-01-02" text t.
+func formatTime(c Context, u Value, v Time) Value {
+	return newString(v.t.Format(goString("text", u)))
+}
+
+// maxUnixSeconds bounds the seconds since the Unix epoch of a Time,
+// about 146 billion years. The time package misbehaves not far beyond.
+const maxUnixSeconds = 1 << 62
+
+var bigSecond = big.NewInt(int64(time.Second))
+
+// nanoseconds returns the number of seconds v as a number of
+// nanoseconds, rounded down. It uses big arithmetic, since a
+// time.Duration covers only about 292 years.
+func nanoseconds(c Context, v Value) *big.Int {
+	var r *big.Rat
+	switch v := v.(type) {
+	case Int:
+		r = new(big.Rat).SetInt64(int64(v))
+	case BigInt:
+		r = new(big.Rat).SetInt(v.Int)
+	case BigRat:
+		r = new(big.Rat).Set(v.Rat)
+	case BigFloat:
+		if isNaN(v) || v.IsInf() {
+			Errorf("time: %s is not a number of seconds", v.Sprint(debugConf))
+		}
+		// Don't build a huge rational only to reject it.
+		if v.MantExp(nil) > 128 {
+			Errorf("time: out of range")
+		}
+		r, _ = v.Rat(nil)
+	default:
+		Errorf("time: %s is not a number of seconds", v.Sprint(debugConf))
+	}
+	r.Mul(r, new(big.Rat).SetInt(bigSecond))
+	// The denominator is positive, so Div rounds down.
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// addNanoseconds returns the time ns nanoseconds after t.
+func addNanoseconds(t time.Time, ns *big.Int) time.Time {
+	sec, nsec := new(big.Int).DivMod(ns, bigSecond, new(big.Int))
+	sec.Add(sec, big.NewInt(t.Unix()))
+	if sec.CmpAbs(big.NewInt(maxUnixSeconds)) > 0 {
+		Errorf("time: out of range")
+	}
+	return time.Unix(sec.Int64(), int64(t.Nanosecond())+nsec.Int64()).In(t.Location())
+}
+
+// timeSub returns the number of seconds from s to t.
+func timeSub(t, s time.Time) Value {
+	ns := big.NewInt(t.Unix())
+	ns.Sub(ns, big.NewInt(s.Unix()))
+	ns.Mul(ns, bigSecond)
+	ns.Add(ns, big.NewInt(int64(t.Nanosecond()-s.Nanosecond())))
+	return BigRat{new(big.Rat).SetFrac(ns, bigSecond)}.shrink()
+}
+
+// since returns the number of seconds from the time v to now.
+func since(c Context, v Value) Value {
+	return timeSub(time.Now(), v.(Time).t)
+}
+
+// humanTime returns the number of seconds v as a string of days,
+// hours, minutes and seconds, such as "1d 1h 1m 1s". Zero units are
+// omitted.
+func humanTime(c Context, v Value) Value {
+	d := nanoseconds(c, v)
+	if d.Sign() == 0 {
+		return newString("0s")
+	}
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+		d.Neg(d)
+	}
+	var parts []string
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+	}
+	n := new(big.Int)
+	for _, u := range units {
+		if n.DivMod(d, big.NewInt(int64(u.size)), d); n.Sign() > 0 {
+			parts = append(parts, n.String()+u.name)
+		}
+	}
+	if d.Sign() > 0 {
+		// Less than a minute remains.
+		parts = append(parts, strconv.FormatFloat(time.Duration(d.Int64()).Seconds(), 'f', -1, 64)+"s")
+	}
+	return newString(sign + strings.Join(parts, " "))
+}
+
+// timeBinary returns u op v when either is a Time. Only arithmetic
+// with seconds and comparisons are defined.
+func timeBinary(c Context, u Value, op string, v Value) Value {
+	t, tok := u.(Time)
+	s, sok := v.(Time)
+	switch {
+	case tok && sok:
+		switch op {
+		case "-":
+			return timeSub(t.t, s.t)
+		case "==":
+			return toInt(t.t.Equal(s.t))
+		case "!=":
+			return toInt(!t.t.Equal(s.t))
+		case "<":
+			return toInt(t.t.Before(s.t))
+		case "<=":
+			return toInt(!t.t.After(s.t))
+		case ">":
+			return toInt(t.t.After(s.t))
+		case ">=":
+			return toInt(!t.t.Before(s.t))
+		}
+	case tok:
+		switch op {
+		case "+":
+			return Time{addNanoseconds(t.t, nanoseconds(c, v))}
+		case "-":
+			ns := nanoseconds(c, v)
+			return Time{addNanoseconds(t.t, ns.Neg(ns))}
+		}
+	case sok:
+		if op == "+" {
+			return Time{addNanoseconds(s.t, nanoseconds(c, u))}
+		}
+	}
+	Errorf("binary %s not implemented on type %s", op, timeType)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package value
+
+import "math/big"
+
+// Tolerant comparison. With a comparison tolerance t, set by
+// )tolerance, rationals and floats x and y compare equal if
+//
+//	|x-y| <= t × max(|x|, |y|)
+//
+// and otherwise compare as usual. A tolerance of zero, the default,
+// makes comparison exact.
+
+// ratCmp compares i and j, which are equal if within the tolerance.
+func ratCmp(c Context, i, j BigRat) int {
+	cmp := i.Cmp(j.Rat)
+	t := c.Config().Tolerance()
+	if cmp == 0 || t == 0 {
+		return cmp
+	}
+	d := new(big.Rat).Sub(i.Rat, j.Rat)
+	d.Abs(d)
+	m := new(big.Rat).Abs(i.Rat)
+	if a := new(big.Rat).Abs(j.Rat); a.Cmp(m) > 0 {
+		m = a
+	}
+	if d.Cmp(m.Mul(m, new(big.Rat).SetFloat64(t))) <= 0 {
+		return 0
+	}
+	return cmp
+}
+
+// floatCmp compares i and j, which are equal if within the tolerance.
+func floatCmp(c Context, i, j BigFloat) int {
+	cmp := i.Cmp(j.Float)
+	t := c.Config().Tolerance()
+	if cmp == 0 || t == 0 || i.IsInf() || j.IsInf() {
+		return cmp
+	}
+	d := newFloat(c).Sub(i.Float, j.Float)
+	d.Abs(d)
+	m := newFloat(c).Abs(i.Float)
+	if a := newFloat(c).Abs(j.Float); a.Cmp(m) > 0 {
+		m = a
+	}
+	if d.Cmp(m.Mul(m, newFloat(c).SetFloat64(t))) <= 0 {
+		return 0
+	}
+	return cmp
+}
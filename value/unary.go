@@ -6,6 +6,7 @@ package value
 
 import (
 	"math/big"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -72,6 +73,11 @@ func vectorSelf(c Context, v Value) Value {
 	return NewVector([]Value{v})
 }
 
+// tallySelf returns the tally of the scalar v.
+func tallySelf(c Context, v Value) Value {
+	return tally(c, Vector{v})
+}
+
 // floatSelf promotes v to type BigFloat.
 func floatSelf(c Context, v Value) Value {
 	conf := c.Config()
@@ -93,9 +99,9 @@ func floatSelf(c Context, v Value) Value {
 // of the value.
 func text(c Context, v Value) Value {
 	str := v.Sprint(c.Config())
-	elem := make([]Value, utf8.RuneCountInString(str))
-	for i, r := range str {
-		elem[i] = Char(r)
+	elem := make([]Value, 0, utf8.RuneCountInString(str))
+	for _, r := range str {
+		elem = append(elem, Char(r))
 	}
 	return NewVector(elem)
 }
@@ -139,6 +145,30 @@ func init() {
 			},
 		},
 
+		{
+			name: "rand",
+			fn: [numType]unaryFn{
+				intType: func(c Context, v Value) Value {
+					return randomArray(c, "rand", v, randomFloat)
+				},
+				vectorType: func(c Context, v Value) Value {
+					return randomArray(c, "rand", v, randomFloat)
+				},
+			},
+		},
+
+		{
+			name: "randn",
+			fn: [numType]unaryFn{
+				intType: func(c Context, v Value) Value {
+					return randomArray(c, "randn", v, randomNormal)
+				},
+				vectorType: func(c Context, v Value) Value {
+					return randomArray(c, "randn", v, randomNormal)
+				},
+			},
+		},
+
 		{
 			name: "+",
 			fn: [numType]unaryFn{
@@ -146,8 +176,21 @@ func init() {
 				bigIntType:   self,
 				bigRatType:   self,
 				bigFloatType: self,
-				vectorType:   self,
-				matrixType:   self,
+				complexType: func(c Context, v Value) Value {
+					return v.(Complex).conj(c)
+				},
+				vectorType: func(c Context, v Value) Value {
+					if !v.(Vector).hasComplex() {
+						return v
+					}
+					return unaryVectorOp(c, "+", v)
+				},
+				matrixType: func(c Context, v Value) Value {
+					if !v.(*Matrix).data.hasComplex() {
+						return v
+					}
+					return unaryMatrixOp(c, "+", v)
+				},
 			},
 		},
 
@@ -167,6 +210,9 @@ func init() {
 				bigFloatType: func(c Context, v Value) Value {
 					return unaryBigFloatOp(c, bigFloatWrap((*big.Float).Neg), v)
 				},
+				complexType: func(c Context, v Value) Value {
+					return v.(Complex).neg(c)
+				},
 			},
 		},
 
@@ -177,7 +223,7 @@ func init() {
 				intType: func(c Context, v Value) Value {
 					i := int64(v.(Int))
 					if i == 0 {
-						Errorf("division by zero")
+						return divideByZero(c, one)
 					}
 					return BigRat{
 						Rat: big.NewRat(0, 1).SetFrac64(1, i),
@@ -204,6 +250,9 @@ func init() {
 						Float: one.Quo(one, f.Float),
 					}.shrink()
 				},
+				complexType: func(c Context, v Value) Value {
+					return Complex{one, zero}.quo(c, v.(Complex))
+				},
 			},
 		},
 
@@ -308,6 +357,37 @@ func init() {
 				bigFloatType: func(c Context, v Value) Value {
 					return unaryBigFloatOp(c, bigFloatWrap((*big.Float).Abs), v)
 				},
+				complexType: func(c Context, v Value) Value {
+					return v.(Complex).abs(c)
+				},
+			},
+		},
+
+		{
+			name:        "real",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType: func(c Context, v Value) Value {
+					return v.(Complex).real
+				},
+			},
+		},
+
+		{
+			name:        "imag",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return zero },
+				bigIntType:   func(c Context, v Value) Value { return zero },
+				bigRatType:   func(c Context, v Value) Value { return zero },
+				bigFloatType: func(c Context, v Value) Value { return zero },
+				complexType: func(c Context, v Value) Value {
+					return v.(Complex).imag
+				},
 			},
 		},
 
@@ -340,7 +420,10 @@ func init() {
 				bigFloatType: func(c Context, v Value) Value {
 					f := v.(BigFloat)
 					if f.Float.IsInf() {
-						Errorf("floor of %s", v.Sprint(c.Config()))
+						if !c.Config().NaN() {
+							Errorf("floor of %s", v.Sprint(c.Config()))
+						}
+						return f
 					}
 					i, acc := f.Int(nil)
 					switch acc {
@@ -354,6 +437,29 @@ func init() {
 			},
 		},
 
+		{
+			name:        "round",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    func(c Context, v Value) Value { return v },
+				bigIntType: func(c Context, v Value) Value { return v },
+				bigRatType: func(c Context, v Value) Value {
+					return roundRat(v.(BigRat).Rat)
+				},
+				bigFloatType: func(c Context, v Value) Value {
+					f := v.(BigFloat)
+					if f.Float.IsInf() {
+						if !c.Config().NaN() {
+							Errorf("round of %s", v.Sprint(c.Config()))
+						}
+						return f
+					}
+					r, _ := f.Rat(nil)
+					return roundRat(r)
+				},
+			},
+		},
+
 		{
 			name:        "ceil",
 			elementwise: true,
@@ -384,7 +490,10 @@ func init() {
 				bigFloatType: func(c Context, v Value) Value {
 					f := v.(BigFloat)
 					if f.Float.IsInf() {
-						Errorf("ceil of %s", v.Sprint(c.Config()))
+						if !c.Config().NaN() {
+							Errorf("ceil of %s", v.Sprint(c.Config()))
+						}
+						return f
 					}
 					i, acc := f.Int(nil)
 					switch acc {
@@ -409,15 +518,21 @@ func init() {
 					if i == 0 {
 						return Vector{}
 					}
-					n := make([]Value, i)
-					for k := range n {
-						n[k] = Int(k + c.Config().Origin())
-					}
-					return NewVector(n)
+					return Range{start: Int(c.Config().Origin()), step: 1, count: int(i)}
 				},
 			},
 		},
 
+		{
+			name: "where",
+			fn: [numType]unaryFn{
+				intType:    func(c Context, v Value) Value { return where(c, NewVector([]Value{v})) },
+				bigIntType: func(c Context, v Value) Value { return where(c, NewVector([]Value{v})) },
+				vectorType: func(c Context, v Value) Value { return where(c, v) },
+				matrixType: func(c Context, v Value) Value { return where(c, v) },
+			},
+		},
+
 		{
 			name: "rho",
 			fn: [numType]unaryFn{
@@ -436,6 +551,15 @@ func init() {
 				bigFloatType: func(c Context, v Value) Value {
 					return Int(0)
 				},
+				complexType: func(c Context, v Value) Value {
+					return Int(0)
+				},
+				boxType: func(c Context, v Value) Value {
+					return Int(0)
+				},
+				timeType: func(c Context, v Value) Value {
+					return Int(0)
+				},
 				vectorType: func(c Context, v Value) Value {
 					return Int(len(v.(Vector)))
 				},
@@ -453,6 +577,9 @@ func init() {
 				bigIntType:   vectorSelf,
 				bigRatType:   vectorSelf,
 				bigFloatType: vectorSelf,
+				complexType:  vectorSelf,
+				boxType:      vectorSelf,
+				timeType:     vectorSelf,
 				vectorType:   self,
 				matrixType: func(c Context, v Value) Value {
 					return v.(*Matrix).data.Copy()
@@ -460,6 +587,132 @@ func init() {
 			},
 		},
 
+		{
+			name: "first",
+			fn: [numType]unaryFn{
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				boxType:      self,
+				timeType:     self,
+				vectorType: func(c Context, v Value) Value {
+					return firstItem(c, v, false)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return firstItem(c, v, false)
+				},
+			},
+		},
+
+		{
+			name: "last",
+			fn: [numType]unaryFn{
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				boxType:      self,
+				timeType:     self,
+				vectorType: func(c Context, v Value) Value {
+					return firstItem(c, v, true)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return firstItem(c, v, true)
+				},
+			},
+		},
+
+		{
+			name: "head",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return Vector{} },
+				charType:     func(c Context, v Value) Value { return Vector{} },
+				bigIntType:   func(c Context, v Value) Value { return Vector{} },
+				bigRatType:   func(c Context, v Value) Value { return Vector{} },
+				bigFloatType: func(c Context, v Value) Value { return Vector{} },
+				complexType:  func(c Context, v Value) Value { return Vector{} },
+				boxType:      func(c Context, v Value) Value { return Vector{} },
+				vectorType: func(c Context, v Value) Value {
+					return tail(c, v, true)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return tail(c, v, true)
+				},
+			},
+		},
+
+		{
+			name: "tail",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return Vector{} },
+				charType:     func(c Context, v Value) Value { return Vector{} },
+				bigIntType:   func(c Context, v Value) Value { return Vector{} },
+				bigRatType:   func(c Context, v Value) Value { return Vector{} },
+				bigFloatType: func(c Context, v Value) Value { return Vector{} },
+				complexType:  func(c Context, v Value) Value { return Vector{} },
+				boxType:      func(c Context, v Value) Value { return Vector{} },
+				vectorType: func(c Context, v Value) Value {
+					return tail(c, v, false)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return tail(c, v, false)
+				},
+			},
+		},
+
+		{
+			name: "enclose",
+			fn: [numType]unaryFn{
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				boxType:      enclose,
+				timeType:     self,
+				vectorType:   enclose,
+				matrixType:   enclose,
+			},
+		},
+
+		{
+			name: "disclose",
+			fn: [numType]unaryFn{
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				boxType:      disclose,
+				timeType:     self,
+				vectorType:   disclose,
+				matrixType:   disclose,
+			},
+		},
+
+		{
+			name: "depth",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return Int(depth(v)) },
+				charType:     func(c Context, v Value) Value { return Int(depth(v)) },
+				bigIntType:   func(c Context, v Value) Value { return Int(depth(v)) },
+				bigRatType:   func(c Context, v Value) Value { return Int(depth(v)) },
+				bigFloatType: func(c Context, v Value) Value { return Int(depth(v)) },
+				complexType:  func(c Context, v Value) Value { return Int(depth(v)) },
+				boxType:      func(c Context, v Value) Value { return Int(depth(v)) },
+				timeType:     func(c Context, v Value) Value { return Int(depth(v)) },
+				vectorType:   func(c Context, v Value) Value { return Int(depth(v)) },
+				matrixType:   func(c Context, v Value) Value { return Int(depth(v)) },
+			},
+		},
+
 		{
 			name: "up",
 			fn: [numType]unaryFn{
@@ -469,10 +722,10 @@ func init() {
 				bigRatType:   self,
 				bigFloatType: self,
 				vectorType: func(c Context, v Value) Value {
-					if c == nil {
-						panic("NIL IN gradeUP")
-					}
-					return v.(Vector).grade(c)
+					return v.(Vector).grade(c, false)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).grade(c, false)
 				},
 			},
 		},
@@ -486,11 +739,163 @@ func init() {
 				bigRatType:   self,
 				bigFloatType: self,
 				vectorType: func(c Context, v Value) Value {
-					x := v.(Vector).grade(c)
-					for i, j := 0, len(x)-1; i < j; i, j = i+1, j-1 {
-						x[i], x[j] = x[j], x[i]
-					}
-					return x
+					return v.(Vector).grade(c, true)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).grade(c, true)
+				},
+			},
+		},
+
+		{
+			name: "mean",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return statistic(c, "mean", v, mean) },
+				bigIntType:   func(c Context, v Value) Value { return statistic(c, "mean", v, mean) },
+				bigRatType:   func(c Context, v Value) Value { return statistic(c, "mean", v, mean) },
+				bigFloatType: func(c Context, v Value) Value { return statistic(c, "mean", v, mean) },
+				vectorType:   func(c Context, v Value) Value { return statistic(c, "mean", v, mean) },
+				matrixType:   func(c Context, v Value) Value { return statistic(c, "mean", v, mean) },
+			},
+		},
+
+		{
+			name: "var",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return statistic(c, "var", v, variance) },
+				bigIntType:   func(c Context, v Value) Value { return statistic(c, "var", v, variance) },
+				bigRatType:   func(c Context, v Value) Value { return statistic(c, "var", v, variance) },
+				bigFloatType: func(c Context, v Value) Value { return statistic(c, "var", v, variance) },
+				vectorType:   func(c Context, v Value) Value { return statistic(c, "var", v, variance) },
+				matrixType:   func(c Context, v Value) Value { return statistic(c, "var", v, variance) },
+			},
+		},
+
+		{
+			name: "stddev",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return statistic(c, "stddev", v, stddev) },
+				bigIntType:   func(c Context, v Value) Value { return statistic(c, "stddev", v, stddev) },
+				bigRatType:   func(c Context, v Value) Value { return statistic(c, "stddev", v, stddev) },
+				bigFloatType: func(c Context, v Value) Value { return statistic(c, "stddev", v, stddev) },
+				vectorType:   func(c Context, v Value) Value { return statistic(c, "stddev", v, stddev) },
+				matrixType:   func(c Context, v Value) Value { return statistic(c, "stddev", v, stddev) },
+			},
+		},
+
+		{
+			name: "median",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return statistic(c, "median", v, median) },
+				bigIntType:   func(c Context, v Value) Value { return statistic(c, "median", v, median) },
+				bigRatType:   func(c Context, v Value) Value { return statistic(c, "median", v, median) },
+				bigFloatType: func(c Context, v Value) Value { return statistic(c, "median", v, median) },
+				vectorType:   func(c Context, v Value) Value { return statistic(c, "median", v, median) },
+				matrixType:   func(c Context, v Value) Value { return statistic(c, "median", v, median) },
+			},
+		},
+
+		{
+			name: "cfrac",
+			fn: [numType]unaryFn{
+				intType:      cfrac,
+				bigIntType:   cfrac,
+				bigRatType:   cfrac,
+				bigFloatType: cfrac,
+			},
+		},
+
+		{
+			name: "fromcfrac",
+			fn: [numType]unaryFn{
+				intType:    func(c Context, v Value) Value { return v },
+				bigIntType: func(c Context, v Value) Value { return v },
+				vectorType: func(c Context, v Value) Value {
+					return fromcfrac(c, v.(Vector))
+				},
+			},
+		},
+
+		{
+			name: "roots",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return roots(c, Vector{v}) },
+				bigIntType:   func(c Context, v Value) Value { return roots(c, Vector{v}) },
+				bigRatType:   func(c Context, v Value) Value { return roots(c, Vector{v}) },
+				bigFloatType: func(c Context, v Value) Value { return roots(c, Vector{v}) },
+				complexType:  func(c Context, v Value) Value { return roots(c, Vector{v}) },
+				vectorType: func(c Context, v Value) Value {
+					return roots(c, v.(Vector))
+				},
+			},
+		},
+
+		{
+			name: "unique",
+			fn: [numType]unaryFn{
+				intType:      vectorSelf,
+				charType:     vectorSelf,
+				bigIntType:   vectorSelf,
+				bigRatType:   vectorSelf,
+				bigFloatType: vectorSelf,
+				complexType:  vectorSelf,
+				vectorType: func(c Context, v Value) Value {
+					return unique(c, v.(Vector))
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).unique(c)
+				},
+			},
+		},
+
+		{
+			name: "tally",
+			fn: [numType]unaryFn{
+				intType:      tallySelf,
+				charType:     tallySelf,
+				bigIntType:   tallySelf,
+				bigRatType:   tallySelf,
+				bigFloatType: tallySelf,
+				complexType:  tallySelf,
+				vectorType: func(c Context, v Value) Value {
+					return tally(c, v.(Vector))
+				},
+				matrixType: func(c Context, v Value) Value {
+					return tally(c, v.(*Matrix).data)
+				},
+			},
+		},
+
+		{
+			name: "sort",
+			fn: [numType]unaryFn{
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				vectorType: func(c Context, v Value) Value {
+					return v.(Vector).sorted(c, false)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).sorted(c, false)
+				},
+			},
+		},
+
+		{
+			name: "sortdown",
+			fn: [numType]unaryFn{
+				intType:      self,
+				charType:     self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				vectorType: func(c Context, v Value) Value {
+					return v.(Vector).sorted(c, true)
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).sorted(c, true)
 				},
 			},
 		},
@@ -503,6 +908,9 @@ func init() {
 				bigIntType:   self,
 				bigRatType:   self,
 				bigFloatType: self,
+				complexType:  self,
+				boxType:      self,
+				timeType:     self,
 				vectorType: func(c Context, v Value) Value {
 					x := v.(Vector).Copy()
 					for i, j := 0, len(x)-1; i < j; i, j = i+1, j-1 {
@@ -539,6 +947,9 @@ func init() {
 				bigIntType:   self,
 				bigRatType:   self,
 				bigFloatType: self,
+				complexType:  self,
+				boxType:      self,
+				timeType:     self,
 				vectorType: func(c Context, v Value) Value {
 					return c.EvalUnary("rot", v)
 				},
@@ -575,6 +986,9 @@ func init() {
 				bigIntType:   self,
 				bigRatType:   self,
 				bigFloatType: self,
+				complexType:  self,
+				boxType:      self,
+				timeType:     self,
 				vectorType: func(c Context, v Value) Value {
 					return v.(Vector).Copy()
 				},
@@ -588,6 +1002,42 @@ func init() {
 			},
 		},
 
+		{
+			name: "det",
+			fn: [numType]unaryFn{
+				intType:      self,
+				bigIntType:   self,
+				bigRatType:   self,
+				bigFloatType: self,
+				complexType:  self,
+				vectorType: func(c Context, v Value) Value {
+					Errorf("determinant requires square matrix")
+					return nil
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).det(c)
+				},
+			},
+		},
+
+		{
+			name: "inv",
+			fn: [numType]unaryFn{
+				intType:      func(c Context, v Value) Value { return c.EvalUnary("/", v) },
+				bigIntType:   func(c Context, v Value) Value { return c.EvalUnary("/", v) },
+				bigRatType:   func(c Context, v Value) Value { return c.EvalUnary("/", v) },
+				bigFloatType: func(c Context, v Value) Value { return c.EvalUnary("/", v) },
+				complexType:  func(c Context, v Value) Value { return c.EvalUnary("/", v) },
+				vectorType: func(c Context, v Value) Value {
+					Errorf("inverse requires square matrix")
+					return nil
+				},
+				matrixType: func(c Context, v Value) Value {
+					return v.(*Matrix).inverse(c)
+				},
+			},
+		},
+
 		{
 			name:        "cos",
 			elementwise: true,
@@ -665,6 +1115,72 @@ func init() {
 			},
 		},
 
+		{
+			name:        "sinh",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      sinh,
+				bigIntType:   sinh,
+				bigRatType:   sinh,
+				bigFloatType: sinh,
+			},
+		},
+
+		{
+			name:        "cosh",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      cosh,
+				bigIntType:   cosh,
+				bigRatType:   cosh,
+				bigFloatType: cosh,
+			},
+		},
+
+		{
+			name:        "tanh",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      tanh,
+				bigIntType:   tanh,
+				bigRatType:   tanh,
+				bigFloatType: tanh,
+			},
+		},
+
+		{
+			name:        "asinh",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      asinh,
+				bigIntType:   asinh,
+				bigRatType:   asinh,
+				bigFloatType: asinh,
+			},
+		},
+
+		{
+			name:        "acosh",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      acosh,
+				bigIntType:   acosh,
+				bigRatType:   acosh,
+				bigFloatType: acosh,
+			},
+		},
+
+		{
+			name:        "atanh",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      atanh,
+				bigIntType:   atanh,
+				bigRatType:   atanh,
+				bigFloatType: atanh,
+			},
+		},
+
 		{
 			name:        "**",
 			elementwise: true,
@@ -673,6 +1189,7 @@ func init() {
 				bigIntType:   func(c Context, v Value) Value { return exp(c, v) },
 				bigRatType:   func(c Context, v Value) Value { return exp(c, v) },
 				bigFloatType: func(c Context, v Value) Value { return exp(c, v) },
+				complexType:  func(c Context, v Value) Value { return v.(Complex).exp(c) },
 			},
 		},
 
@@ -687,11 +1204,87 @@ func init() {
 			},
 		},
 
+		{
+			name:        "gamma",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      gamma,
+				bigIntType:   gamma,
+				bigRatType:   gamma,
+				bigFloatType: gamma,
+			},
+		},
+
+		{
+			name:        "lgamma",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:      lgamma,
+				bigIntType:   lgamma,
+				bigRatType:   lgamma,
+				bigFloatType: lgamma,
+			},
+		},
+
+		{
+			name:        "isprime",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    isprime,
+				bigIntType: isprime,
+			},
+		},
+
+		{
+			name:        "nextprime",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				intType:    nextprime,
+				bigIntType: nextprime,
+			},
+		},
+
+		{
+			name: "factor",
+			fn: [numType]unaryFn{
+				intType:    factor,
+				bigIntType: factor,
+			},
+		},
+
 		{
 			name:        "char",
 			elementwise: true,
 			fn: [numType]unaryFn{
-				intType: func(c Context, v Value) Value { return Char(v.(Int)).validate() },
+				intType:      newChar,
+				bigIntType:   newChar,
+				bigRatType:   newChar,
+				bigFloatType: newChar,
+			},
+		},
+
+		{
+			name:        "upper",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				charType: func(c Context, v Value) Value { return Char(unicode.ToUpper(rune(v.(Char)))) },
+			},
+		},
+
+		{
+			name:        "lower",
+			elementwise: true,
+			fn: [numType]unaryFn{
+				charType: func(c Context, v Value) Value { return Char(unicode.ToLower(rune(v.(Char)))) },
+			},
+		},
+
+		{
+			name: "trim",
+			fn: [numType]unaryFn{
+				charType:   trim,
+				vectorType: trim,
+				matrixType: trim,
 			},
 		},
 
@@ -710,6 +1303,9 @@ func init() {
 				bigIntType:   func(c Context, v Value) Value { return text(c, v) },
 				bigRatType:   func(c Context, v Value) Value { return text(c, v) },
 				bigFloatType: func(c Context, v Value) Value { return text(c, v) },
+				complexType:  func(c Context, v Value) Value { return text(c, v) },
+				boxType:      func(c Context, v Value) Value { return text(c, v) },
+				timeType:     func(c Context, v Value) Value { return text(c, v) },
 				vectorType:   func(c Context, v Value) Value { return text(c, v) },
 				matrixType:   func(c Context, v Value) Value { return text(c, v) },
 			},
@@ -732,6 +1328,117 @@ func init() {
 			},
 		},
 
+		{
+			name: "date",
+			fn: [numType]unaryFn{
+				intType:      date,
+				charType:     date,
+				bigIntType:   date,
+				bigRatType:   date,
+				bigFloatType: date,
+				timeType:     date,
+				vectorType:   date,
+			},
+		},
+
+		{
+			name: "since",
+			fn: [numType]unaryFn{
+				timeType: since,
+			},
+		},
+
+		{
+			name: "humantime",
+			fn: [numType]unaryFn{
+				intType:      humanTime,
+				bigIntType:   humanTime,
+				bigRatType:   humanTime,
+				bigFloatType: humanTime,
+			},
+		},
+
+		{
+			name: "base64",
+			fn: [numType]unaryFn{
+				intType:    encodeBase64,
+				charType:   encodeBase64,
+				vectorType: encodeBase64,
+			},
+		},
+
+		{
+			name: "unbase64",
+			fn: [numType]unaryFn{
+				charType:   decodeBase64,
+				vectorType: decodeBase64,
+			},
+		},
+
+		{
+			name: "hex",
+			fn: [numType]unaryFn{
+				intType:    encodeHex,
+				charType:   encodeHex,
+				vectorType: encodeHex,
+			},
+		},
+
+		{
+			name: "unhex",
+			fn: [numType]unaryFn{
+				charType:   decodeHex,
+				vectorType: decodeHex,
+			},
+		},
+
+		{
+			name: "sh",
+			fn: [numType]unaryFn{
+				charType:   sh,
+				vectorType: sh,
+			},
+		},
+
+		{
+			name: "getenv",
+			fn: [numType]unaryFn{
+				charType:   getenv,
+				vectorType: getenv,
+			},
+		},
+
+		{
+			name: "json",
+			fn: [numType]unaryFn{
+				intType:      toJSON,
+				charType:     toJSON,
+				bigIntType:   toJSON,
+				bigRatType:   toJSON,
+				bigFloatType: toJSON,
+				complexType:  toJSON,
+				boxType:      toJSON,
+				vectorType:   toJSON,
+				matrixType:   toJSON,
+			},
+		},
+
+		{
+			name: "unjson",
+			fn: [numType]unaryFn{
+				charType:   fromJSON,
+				vectorType: fromJSON,
+			},
+		},
+
+		{
+			name: "csv",
+			fn: [numType]unaryFn{
+				charType:   readCSV,
+				vectorType: readCSV,
+			},
+		},
+
 		{
 			name:        "float",
 			elementwise: true,
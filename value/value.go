@@ -37,24 +37,93 @@ type Value interface {
 	toType(*config.Config, valueType) Value
 }
 
+// PrintString returns the text of v as the interpreter prints it: like
+// Sprint, but with long vectors and matrix rows wrapped to the configured
+// output width. Sprint itself never wraps, so text computed from a
+// value, as by text, json or csv, does not depend on the width.
+func PrintString(conf *config.Config, v Value) string {
+	switch v := v.(type) {
+	case Vector:
+		return v.sprint(conf, conf.OutputWidth())
+	case *Matrix:
+		return v.sprint(conf, conf.OutputWidth())
+	case Range:
+		return v.materialize(conf).sprint(conf, conf.OutputWidth())
+	}
+	return v.Sprint(conf)
+}
+
 // Error is the type we recognize as a recoverable run-time error.
-type Error string
+// Errorf sets only the message. As the error passes out through the
+// evaluation of an expression, the operator being applied, if any,
+// fills in the rest.
+type Error struct {
+	Msg     string
+	Op      string // The operator that failed, such as "/".
+	Operand string // The types and shapes of its operands, as in "int / int".
+	Pos     string // Where the operator appears, as in "file.ivy:3".
+}
 
 func (err Error) Error() string {
-	return string(err)
+	return err.Msg
+}
+
+// Detail returns the message prefixed by the operator, unless the
+// message already begins with it, and followed by the operands.
+func (err Error) Detail() string {
+	if err.Op == "" {
+		return err.Msg
+	}
+	msg := err.Msg
+	if !strings.HasPrefix(msg, err.Op+":") {
+		msg = err.Op + ": " + msg
+	}
+	return fmt.Sprintf("%s (%s)", msg, err.Operand)
 }
 
 // Errorf panics with the formatted string, with type Error.
 func Errorf(format string, args ...interface{}) {
-	panic(Error(fmt.Sprintf(format, args...)))
+	panic(Error{Msg: fmt.Sprintf(format, args...)})
+}
+
+// parseNumber is like Parse but reports failure, including the errors
+// Parse raises for malformed input, only by its boolean result.
+func parseNumber(conf *config.Config, s string) (v Value, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			if _, isError := err.(Error); !isError {
+				panic(err)
+			}
+			v, ok = nil, false
+		}
+	}()
+	v, err := Parse(conf, s)
+	return v, err == nil
+}
+
+// ParseLiteral parses s, a number literal accepted by the scanner. It
+// drops the underscores and digit grouping separators the scanner allows
+// between digits, whose placement it has checked, and calls Parse. Text
+// from elsewhere, such as a data file, must go to Parse directly, so that
+// 1,234 there is not taken for a number.
+func ParseLiteral(conf *config.Config, s string) (Value, error) {
+	s = strings.ReplaceAll(s, "_", "")
+	if sep, _ := conf.Grouping(); sep != "" {
+		s = strings.ReplaceAll(s, sep, "")
+	}
+	return Parse(conf, s)
 }
 
 func Parse(conf *config.Config, s string) (Value, error) {
+	// Is it complex? In bases above 19, j is a digit.
+	if strings.ContainsRune(s, 'j') && conf.InputBase() < 20 {
+		return parseComplex(conf, s)
+	}
 	// Is it a rational? If so, it's tricky.
 	if strings.ContainsRune(s, '/') {
 		elems := strings.Split(s, "/")
 		if len(elems) != 2 {
-			panic("bad rat")
+			return nil, fmt.Errorf("bad rational %q", s)
 		}
 		num, err := Parse(conf, elems[0])
 		if err != nil {
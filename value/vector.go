@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"unicode/utf8"
 
 	"robpike.io/ivy/config"
 )
@@ -19,7 +20,22 @@ func (v Vector) String() string {
 }
 
 func (v Vector) Sprint(conf *config.Config) string {
-	return v.makeString(conf, !v.AllChars())
+	return v.sprint(conf, 0)
+}
+
+// sprint is like Sprint but, if width is positive, wraps the elements
+// onto lines of at most width characters where possible.
+func (v Vector) sprint(conf *config.Config, width int) string {
+	if v.AllChars() {
+		return v.makeString(conf, false)
+	}
+	if v.hasBox() {
+		return v.boxString(conf)
+	}
+	if width > 0 {
+		return v.wrapString(conf, width)
+	}
+	return v.makeString(conf, true)
 }
 
 func (v Vector) Rank() int {
@@ -47,6 +63,34 @@ func (v Vector) makeString(conf *config.Config, spaces bool) string {
 	return b.String()
 }
 
+// wrapIndent is the indentation of continuation lines when output is
+// wrapped to the configured width.
+const wrapIndent = "      "
+
+// wrapString is like makeString with spaces, but breaks the line between
+// elements so that, where possible, no line is longer than width.
+func (v Vector) wrapString(conf *config.Config, width int) string {
+	var b bytes.Buffer
+	col := 0
+	for i, elem := range v {
+		s := elem.Sprint(conf)
+		n := utf8.RuneCountInString(s)
+		if i > 0 {
+			if col+1+n > width {
+				b.WriteByte('\n')
+				b.WriteString(wrapIndent)
+				col = len(wrapIndent)
+			} else {
+				b.WriteByte(' ')
+				col++
+			}
+		}
+		b.WriteString(s)
+		col += n
+	}
+	return b.String()
+}
+
 // AllChars reports whether the vector contains only Chars.
 func (v Vector) AllChars() bool {
 	for _, c := range v {
@@ -57,6 +101,16 @@ func (v Vector) AllChars() bool {
 	return true
 }
 
+// hasComplex reports whether any element of the vector is complex.
+func (v Vector) hasComplex() bool {
+	for _, c := range v {
+		if _, ok := c.Inner().(Complex); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func NewVector(elems []Value) Vector {
 	return Vector(elems)
 }
@@ -127,15 +181,13 @@ func doRotate(dst, src []Value, j int) {
 	}
 }
 
-// grade returns as a Vector the indexes that sort the vector into increasing order
-func (v Vector) grade(c Context) Vector {
-	x := make([]int, len(v))
-	for i := range x {
-		x[i] = i
-	}
-	sort.Sort(&gradeIndex{c: c, v: v, x: x})
+// grade returns as a Vector the indexes that sort the items into
+// increasing order, or decreasing order if down is set. The sort is
+// stable: equal items keep their original order.
+func grade(c Context, items []Value, down bool) Vector {
+	x := sortedIndexes(c, items, down)
 	origin := c.Config().Origin()
-	result := make([]Value, len(v))
+	result := make([]Value, len(items))
 	for i, index := range x {
 		n := origin + index
 		if n > maxInt { // Unlikely but be careful.
@@ -147,23 +199,224 @@ func (v Vector) grade(c Context) Vector {
 	return NewVector(result)
 }
 
+// sortedIndexes returns the indexes, counting from zero, that sort
+// the items as for grade.
+func sortedIndexes(c Context, items []Value, down bool) []int {
+	x := make([]int, len(items))
+	for i := range x {
+		x[i] = i
+	}
+	sort.Stable(&gradeIndex{c: c, v: items, x: x, down: down})
+	return x
+}
+
+// grade returns as a Vector the indexes that sort the vector into
+// increasing order, or decreasing order if down is set.
+func (v Vector) grade(c Context, down bool) Vector {
+	return grade(c, v, down)
+}
+
+// sorted returns a copy of v sorted into increasing order, or
+// decreasing order if down is set.
+func (v Vector) sorted(c Context, down bool) Vector {
+	result := make(Vector, len(v))
+	for i, index := range sortedIndexes(c, v, down) {
+		result[i] = v[index]
+	}
+	return result
+}
+
+// compare returns -1, 0, or 1 according to whether a is less than,
+// equal to, or greater than b. Vectors, which are the rows of a
+// matrix being graded, compare lexicographically.
+func compare(c Context, a, b Value) int {
+	if a, ok := a.(Vector); ok {
+		b := b.(Vector)
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if cmp := compare(c, a[i], b[i]); cmp != 0 {
+				return cmp
+			}
+		}
+		switch {
+		case len(a) < len(b):
+			return -1
+		case len(a) > len(b):
+			return 1
+		}
+		return 0
+	}
+	switch {
+	case toBool(c.EvalBinary(a, "<", b)):
+		return -1
+	case toBool(c.EvalBinary(b, "<", a)):
+		return 1
+	}
+	return 0
+}
+
+// collate returns a copy of v, a vector or matrix of chars, with each
+// char replaced by its index in the alphabet, so that grading the result
+// sorts v in the alphabet's order. Chars not in the alphabet sort after
+// all those that are.
+func collate(op string, alphabet Value, v Value) Value {
+	_, chars := shapeAndData(alphabet)
+	rank := make(map[Char]int)
+	for i, x := range chars {
+		ch, ok := x.(Char)
+		if !ok {
+			Errorf("%s: left operand must be chars", op)
+		}
+		if _, ok := rank[ch]; !ok {
+			rank[ch] = i
+		}
+	}
+	shape, data := shapeAndData(v)
+	result := make(Vector, len(data))
+	for i, x := range data {
+		ch, ok := x.(Char)
+		if !ok {
+			Errorf("%s: right operand must be chars", op)
+		}
+		r, ok := rank[ch]
+		if !ok {
+			r = len(chars)
+		}
+		result[i] = Int(r)
+	}
+	if _, ok := v.(*Matrix); ok {
+		return NewMatrix(shape, result)
+	}
+	return result
+}
+
 // membership creates a vector of size len(u) reporting
 // whether each element is an element of v.
 // TODO: N*M algorithm - can we do better?
 func membership(c Context, u, v Vector) Value {
 	values := make([]Value, len(u))
 	for i, x := range u {
-		values[i] = Int(0)
+		values[i] = toInt(v.contains(c, x))
+	}
+	return NewVector(values).shrink()
+}
+
+// count creates a vector of size len(u) reporting
+// how many times each element of u occurs in v.
+func count(c Context, u, v Vector) Value {
+	values := make([]Value, len(u))
+	for i, x := range u {
+		n := 0
 		for _, y := range v {
-			if c.EvalBinary(x, "==", y) == Int(1) {
-				values[i] = Int(1)
-				break
+			if sameValue(c, x, y) {
+				n++
 			}
 		}
+		values[i] = Int(n)
 	}
 	return NewVector(values).shrink()
 }
 
+// tally returns a matrix whose first row holds the distinct elements
+// of v, in order of first appearance, and whose second row holds how
+// many times each occurs.
+func tally(c Context, v Vector) Value {
+	elems := Vector{}
+	var counts []int
+Loop:
+	for _, x := range v {
+		for i, y := range elems {
+			if sameValue(c, x, y) {
+				counts[i]++
+				continue Loop
+			}
+		}
+		elems = append(elems, x)
+		counts = append(counts, 1)
+	}
+	data := make(Vector, 2*len(elems))
+	copy(data, elems)
+	for i, n := range counts {
+		data[len(elems)+i] = Int(n)
+	}
+	return NewMatrix([]int{2, len(elems)}, data)
+}
+
+// contains reports whether v has an element equal to x.
+// A char is never equal to a number.
+func (v Vector) contains(c Context, x Value) bool {
+	for _, y := range v {
+		if sameValue(c, x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal reports whether v and x have the same length and equal elements.
+func (v Vector) equal(c Context, x Vector) bool {
+	if len(v) != len(x) {
+		return false
+	}
+	for i := range v {
+		if !sameValue(c, v[i], x[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameValue reports whether x and y are equal. Unlike ==, it accepts
+// a char and a number, which are never equal.
+func sameValue(c Context, x, y Value) bool {
+	_, xIsChar := x.Inner().(Char)
+	if _, yIsChar := y.Inner().(Char); xIsChar != yIsChar {
+		return false
+	}
+	return c.EvalBinary(x, "==", y) == Int(1)
+}
+
+// unique returns the distinct elements of v, in order of first appearance.
+func unique(c Context, v Vector) Value {
+	return union(c, v, nil)
+}
+
+// union returns the distinct elements of u and v, in order of first appearance.
+func union(c Context, u, v Vector) Value {
+	result := Vector{}
+	for _, w := range []Vector{u, v} {
+		for _, x := range w {
+			if !result.contains(c, x) {
+				result = append(result, x)
+			}
+		}
+	}
+	return result
+}
+
+// intersect returns the distinct elements of u that are also in v,
+// in order of first appearance.
+func intersect(c Context, u, v Vector) Value {
+	result := Vector{}
+	for _, x := range u {
+		if v.contains(c, x) && !result.contains(c, x) {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// difference returns the distinct elements of u that are not in v,
+// in order of first appearance.
+func difference(c Context, u, v Vector) Value {
+	result := Vector{}
+	for _, x := range u {
+		if !v.contains(c, x) && !result.contains(c, x) {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
 func (v Vector) shrink() Value {
 	if len(v) == 1 {
 		return v[0]
@@ -172,9 +425,10 @@ func (v Vector) shrink() Value {
 }
 
 type gradeIndex struct {
-	c Context
-	v Vector
-	x []int
+	c    Context
+	v    []Value
+	x    []int
+	down bool
 }
 
 func (g *gradeIndex) Len() int {
@@ -182,7 +436,11 @@ func (g *gradeIndex) Len() int {
 }
 
 func (g *gradeIndex) Less(i, j int) bool {
-	return toBool(g.c.EvalBinary(g.v[g.x[i]], "<", g.v[g.x[j]]))
+	cmp := compare(g.c, g.v[g.x[i]], g.v[g.x[j]])
+	if g.down {
+		return cmp > 0
+	}
+	return cmp < 0
 }
 
 func (g *gradeIndex) Swap(i, j int) {